@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	hw02unpackstring "github.com/fixme_my_friend/hw02_unpack_string"
+)
+
+var (
+	pack, check, multiDigit, lenient bool
+	workers, chunkLines              int
+)
+
+func init() {
+	flag.BoolVar(&pack, "pack", false, "pack lines instead of unpacking them")
+	flag.BoolVar(&check, "check", false, "only validate lines, report errors as file:line:column")
+	flag.BoolVar(&multiDigit, "multi-digit", false, "allow repeat counts longer than one digit")
+	flag.BoolVar(&lenient, "lenient", false, "treat malformed sequences as plain runes")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "number of parallel workers")
+	flag.IntVar(&chunkLines, "chunk", 1000, "number of lines processed by a worker at once")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\n"+
+			"Reads stdin if no files are given. Errors go to stderr, an invalid line is written out\n"+
+			"as an empty line, so output lines match input lines.\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+	if workers <= 0 || chunkLines <= 0 {
+		fmt.Fprintln(os.Stderr, "-workers and -chunk must be > 0")
+		os.Exit(2)
+	}
+
+	var opts []hw02unpackstring.Option
+	if multiDigit {
+		opts = append(opts, hw02unpackstring.WithMultiDigitCounts())
+	}
+	if lenient {
+		opts = append(opts, hw02unpackstring.WithLenient())
+	}
+	unpacker, err := hw02unpackstring.NewUnpacker(opts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	p := processor{convert: unpacker.Unpack, check: check, workers: workers, chunkLines: chunkLines}
+	if pack {
+		p.convert = hw02unpackstring.Pack
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	failed := false
+	for _, name := range files {
+		invalid, err := processFile(p, name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		failed = failed || invalid > 0
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func processFile(p processor, name string) (int, error) {
+	var in io.Reader = os.Stdin
+	if name == "-" {
+		name = "<stdin>"
+	} else {
+		f, err := os.Open(name)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		in = f
+	}
+	return p.run(name, in, os.Stdout, os.Stderr)
+}
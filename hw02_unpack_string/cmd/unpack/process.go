@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	hw02unpackstring "github.com/fixme_my_friend/hw02_unpack_string"
+)
+
+const maxLineSize = 64 << 20 // bufio.Scanner default of 64 KiB is too small for packed logs
+
+type convertFunc func(string) (string, error)
+
+// processor converts input line by line, chunks of lines are handled by parallel workers
+// and written out in the input order.
+type processor struct {
+	convert    convertFunc
+	check      bool // only validate, don't write converted lines
+	workers    int
+	chunkLines int
+}
+
+type chunk struct {
+	first  int // number of the first line in chunk, starting from 1
+	lines  []string
+	result chan<- chunkResult
+}
+
+type chunkResult struct {
+	lines   []string
+	reports []string
+}
+
+// run processes r and returns the number of invalid lines, out receives converted lines with an empty
+// line for every invalid one, report gets "name:line:column: error" for every invalid line.
+func (p processor) run(name string, r io.Reader, out, report io.Writer) (int, error) {
+	chunks := make(chan chunk)
+	order := make(chan chan chunkResult, p.workers)
+	var scanErr error
+
+	go func() {
+		defer close(order)
+		defer close(chunks)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		c := chunk{first: 1}
+		send := func() {
+			result := make(chan chunkResult, 1)
+			c.result = result
+			order <- result
+			chunks <- c
+			c = chunk{first: c.first + len(c.lines)}
+		}
+		for scanner.Scan() {
+			c.lines = append(c.lines, scanner.Text())
+			if len(c.lines) == p.chunkLines {
+				send()
+			}
+		}
+		if len(c.lines) > 0 {
+			send()
+		}
+		scanErr = scanner.Err()
+	}()
+
+	wg := sync.WaitGroup{}
+	wg.Add(p.workers)
+	for range p.workers {
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				c.result <- p.convertChunk(name, c)
+			}
+		}()
+	}
+
+	// the rest of results is drained after a write error, so the goroutines above can finish
+	invalid := 0
+	w := bufio.NewWriter(out)
+	var writeErr error
+	for result := range order {
+		res := <-result
+		invalid += len(res.reports)
+		for _, line := range res.reports {
+			if _, err := fmt.Fprintln(report, line); err != nil && writeErr == nil {
+				writeErr = err
+			}
+		}
+		for _, line := range res.lines {
+			if _, err := fmt.Fprintln(w, line); err != nil && writeErr == nil {
+				writeErr = err
+			}
+		}
+	}
+	wg.Wait()
+
+	if writeErr == nil {
+		writeErr = w.Flush()
+	}
+	return invalid, errors.Join(scanErr, writeErr)
+}
+
+func (p processor) convertChunk(name string, c chunk) chunkResult {
+	var res chunkResult
+	if !p.check {
+		res.lines = make([]string, 0, len(c.lines))
+	}
+	for i, line := range c.lines {
+		converted, err := p.convert(line)
+		if err != nil {
+			res.reports = append(res.reports, formatReport(name, c.first+i, err))
+			// an empty line stands for the invalid one, so output lines still match input lines
+			converted = ""
+		}
+		if !p.check {
+			res.lines = append(res.lines, converted)
+		}
+	}
+	return res
+}
+
+func formatReport(name string, line int, err error) string {
+	var parseErr *hw02unpackstring.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Sprintf("%s:%d:%d: %s", name, line, parseErr.Index+1, err)
+	}
+	return fmt.Sprintf("%s:%d: %s", name, line, err)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	hw02unpackstring "github.com/fixme_my_friend/hw02_unpack_string"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor(t *testing.T) {
+	const linesCount = 10_000
+	var input, expected strings.Builder
+	for i := range linesCount {
+		n := strconv.Itoa(i % 10)
+		input.WriteString("a" + n + "b\\" + n + "\n")
+		expected.WriteString(strings.Repeat("a", i%10) + "b" + n + "\n")
+	}
+
+	t.Run("unpack in order", func(t *testing.T) {
+		for _, workers := range []int{1, 4, 16} {
+			p := processor{convert: hw02unpackstring.Unpack, workers: workers, chunkLines: 7}
+			var out, report bytes.Buffer
+			invalid, err := p.run("in", strings.NewReader(input.String()), &out, &report)
+			require.NoError(t, err)
+			require.Equal(t, 0, invalid)
+			require.Empty(t, report.String())
+			require.Equal(t, expected.String(), out.String())
+		}
+	})
+
+	t.Run("pack", func(t *testing.T) {
+		p := processor{convert: hw02unpackstring.Pack, workers: 4, chunkLines: 100}
+		var out, report bytes.Buffer
+		invalid, err := p.run("in", strings.NewReader("aaaab\nqwe45\n"), &out, &report)
+		require.NoError(t, err)
+		require.Equal(t, 0, invalid)
+		require.Equal(t, "a4b\nqwe\\4\\5\n", out.String())
+	})
+
+	t.Run("invalid lines keep their place", func(t *testing.T) {
+		p := processor{convert: hw02unpackstring.Unpack, workers: 3, chunkLines: 2}
+		var out, report bytes.Buffer
+		invalid, err := p.run("in.txt", strings.NewReader("a2\n3abc\nb3\nпривет10\nc\n"), &out, &report)
+		require.NoError(t, err)
+		require.Equal(t, 2, invalid)
+		require.Equal(t, "aa\n\nbbb\n\nc\n", out.String())
+		require.Len(t, strings.Split(strings.TrimSpace(report.String()), "\n"), 2)
+	})
+
+	t.Run("check", func(t *testing.T) {
+		p := processor{convert: hw02unpackstring.Unpack, check: true, workers: 3, chunkLines: 2}
+		var out, report bytes.Buffer
+		invalid, err := p.run("in.txt", strings.NewReader("a4bc2d5e\n3abc\nok\nпривет10\n"), &out, &report)
+		require.NoError(t, err)
+		require.Equal(t, 2, invalid)
+		require.Empty(t, out.String())
+		require.Equal(t, []string{
+			`in.txt:2:1: invalid string: leading digit "3" at byte offset 0 (rune 0)`,
+			`in.txt:4:7: invalid string: double digit "10" at byte offset 12 (rune 6)`,
+		}, strings.Split(strings.TrimSpace(report.String()), "\n"))
+	})
+}
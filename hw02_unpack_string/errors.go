@@ -0,0 +1,53 @@
+package hw02unpackstring
+
+import (
+	"fmt"
+)
+
+// Reason tells why a packed string was rejected.
+type Reason int
+
+const (
+	ReasonLeadingDigit   Reason = iota + 1 // repeat count with no rune before it, e.g. "3abc"
+	ReasonDoubleDigit                      // second digit of a count in single digit mode, e.g. "a10"
+	ReasonDanglingEscape                   // escape rune at the end of input, e.g. `abc\`
+	ReasonInvalidEscape                    // escaped rune is neither a digit nor the escape rune, e.g. `\n`
+	ReasonInvalidUTF8                      // byte that doesn't start a valid UTF-8 sequence, e.g. "a\xff"
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonLeadingDigit:
+		return "leading digit"
+	case ReasonDoubleDigit:
+		return "double digit"
+	case ReasonDanglingEscape:
+		return "dangling escape"
+	case ReasonInvalidEscape:
+		return "invalid escape target"
+	case ReasonInvalidUTF8:
+		return "invalid UTF-8"
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// ParseError points at the exact place of invalid input, it matches ErrInvalidString through errors.Is,
+// and ErrInvalidUTF8 too if the input is not valid UTF-8.
+type ParseError struct {
+	Index   int64  // rune index of the bad sequence
+	Offset  int64  // byte offset of the bad sequence
+	Snippet string // the bad sequence itself
+	Reason  Reason
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s %q at byte offset %d (rune %d)", ErrInvalidString, e.Reason, e.Snippet, e.Offset, e.Index)
+}
+
+func (e *ParseError) Unwrap() error {
+	return ErrInvalidString
+}
+
+func (e *ParseError) Is(target error) bool {
+	return target == ErrInvalidUTF8 && e.Reason == ReasonInvalidUTF8
+}
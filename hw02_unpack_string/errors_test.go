@@ -0,0 +1,40 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ParseError
+	}{
+		{input: "3abc", expected: ParseError{Index: 0, Offset: 0, Snippet: "3", Reason: ReasonLeadingDigit}},
+		{input: "aaa10b", expected: ParseError{Index: 3, Offset: 3, Snippet: "10", Reason: ReasonDoubleDigit}},
+		{input: "ыы45", expected: ParseError{Index: 2, Offset: 4, Snippet: "45", Reason: ReasonDoubleDigit}},
+		{input: `abc\`, expected: ParseError{Index: 3, Offset: 3, Snippet: `\`, Reason: ReasonDanglingEscape}},
+		{input: `щи\щи`, expected: ParseError{Index: 2, Offset: 4, Snippet: `\щ`, Reason: ReasonInvalidEscape}},
+		{input: "ы\xff2", expected: ParseError{Index: 1, Offset: 2, Snippet: "\xff", Reason: ReasonInvalidUTF8}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Unpack(tc.input)
+			require.ErrorIs(t, err, ErrInvalidString)
+
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr), "actual error %q", err)
+			require.Equal(t, tc.expected, *parseErr)
+		})
+	}
+
+	t.Run("message", func(t *testing.T) {
+		err := &ParseError{Index: 1, Offset: 2, Snippet: `\n`, Reason: ReasonInvalidEscape}
+		require.Equal(t, `invalid string: invalid escape target "\\n" at byte offset 2 (rune 1)`, err.Error())
+		require.Equal(t, "Reason(0)", Reason(0).String())
+	})
+}
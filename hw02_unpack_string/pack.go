@@ -0,0 +1,48 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxCount = 9
+
+var ErrInvalidUTF8 = errors.New("input is not valid UTF-8")
+
+// Pack is the inverse of Unpack: it produces the shortest string s for which Unpack(s) == input.
+// Digits and backslashes are escaped, runs of a rune are collapsed into counts of up to 9.
+func Pack(input string) (string, error) {
+	if !utf8.ValidString(input) {
+		return "", ErrInvalidUTF8
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(input))
+	for len(input) > 0 {
+		c, size := utf8.DecodeRuneInString(input)
+		run := 1
+		for run*size < len(input) && strings.HasPrefix(input[run*size:], input[:size]) {
+			run++
+		}
+		input = input[run*size:]
+		writeRun(&sb, c, run)
+	}
+	return sb.String(), nil
+}
+
+// writeRun encodes run copies of c as full chunks of 9 plus a remainder,
+// a remainder of one rune is written without a count.
+func writeRun(sb *strings.Builder, c rune, run int) {
+	for run > 0 {
+		chunk := min(run, maxCount)
+		run -= chunk
+		if isDigit(c) || c == escapeRune {
+			sb.WriteRune(escapeRune)
+		}
+		sb.WriteRune(c)
+		if chunk > 1 {
+			sb.WriteByte(byte('0' + chunk))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package hw02unpackstring
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: "abcd", expected: "abcd"},
+		{input: "aaaabccddddde", expected: "a4bc2d5e"},
+		{input: "aaaaaaaaaaa", expected: "a9a2"},
+		{input: "aaaaaaaaaa", expected: "a9a"},
+		{input: "d\n\n\n\n\nabc", expected: "d\n5abc"},
+		{input: "приветтт", expected: "привет3"},
+		{input: "qwe45", expected: `qwe\4\5`},
+		{input: "qwe44444", expected: `qwe\45`},
+		{input: `qwe\\\\\`, expected: `qwe\\5`},
+		{input: `qwe\3`, expected: `qwe\\\3`},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := Pack(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+
+	t.Run("invalid utf-8", func(t *testing.T) {
+		_, err := Pack("a\xffb")
+		require.ErrorIs(t, err, ErrInvalidUTF8)
+	})
+}
+
+func FuzzPackUnpack(f *testing.F) {
+	for _, seed := range []string{"", "a", "aaaabccddddde", "qwe44444", `qwe\\\\\`, "ыыыыыыыыыыы", "0\\\n9"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		packed, err := Pack(input)
+		if !utf8.ValidString(input) {
+			require.ErrorIs(t, err, ErrInvalidUTF8)
+			return
+		}
+		require.NoError(t, err)
+		require.LessOrEqual(t, utf8.RuneCountInString(packed), 2*utf8.RuneCountInString(input))
+
+		unpacked, err := Unpack(packed)
+		require.NoError(t, err)
+		require.Equal(t, input, unpacked)
+	})
+}
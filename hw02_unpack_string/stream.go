@@ -0,0 +1,151 @@
+package hw02unpackstring
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// UnpackStream decodes r into w rune by rune, so memory usage does not depend on the input size.
+// On invalid input, including bytes that are not valid UTF-8, the returned error is a *ParseError
+// that reports the position of the bad sequence.
+// Output written before the error is not rolled back.
+func UnpackStream(r io.Reader, w io.Writer) error {
+	return defaultUnpacker.UnpackStream(r, w)
+}
+
+func (u *Unpacker) UnpackStream(r io.Reader, w io.Writer) error {
+	in := bufio.NewReader(r)
+	d := decoder{Unpacker: u, out: bufio.NewWriter(w)}
+
+	for {
+		c, size, err := in.ReadRune()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if c == utf8.RuneError && size == 1 {
+			// ReadRune turns an invalid byte into U+FFFD, read the byte itself for the error
+			_ = in.UnreadRune()
+			b, _ := in.ReadByte()
+			return &ParseError{Index: d.index, Offset: d.offset, Snippet: string([]byte{b}), Reason: ReasonInvalidUTF8}
+		}
+		if err := d.next(c); err != nil {
+			return err
+		}
+		d.offset += int64(size)
+		d.index++
+	}
+
+	if d.escaped {
+		if !d.lenient {
+			return &ParseError{
+				Index: d.escIndex, Offset: d.escOffset, Snippet: string(d.escape), Reason: ReasonDanglingEscape,
+			}
+		}
+		d.escaped = false
+		d.hold(d.escape, d.escOffset)
+	}
+	if err := d.flush(); err != nil {
+		return err
+	}
+	return d.out.Flush()
+}
+
+// decoder keeps the state of a single UnpackStream call.
+type decoder struct {
+	*Unpacker
+	out     *bufio.Writer
+	written int64
+	offset  int64 // byte offset of the current rune
+	index   int64 // rune index of the current rune
+
+	pending       rune // rune waiting for an optional repeat count
+	pendingOffset int64
+	hasPending    bool
+	count         int64
+	hasCount      bool
+
+	escaped   bool
+	escOffset int64 // byte offset of the last escape rune
+	escIndex  int64
+}
+
+func (d *decoder) next(c rune) error {
+	switch {
+	case d.escaped:
+		d.escaped = false
+		if isDigit(c) || c == d.escape {
+			d.hold(c, d.escOffset)
+			return nil
+		}
+		if !d.lenient {
+			return &ParseError{
+				Index: d.escIndex, Offset: d.escOffset, Snippet: string([]rune{d.escape, c}), Reason: ReasonInvalidEscape,
+			}
+		}
+		// the escape rune is a plain one, c is handled as usual
+		d.hold(d.escape, d.escOffset)
+		return d.next(c)
+	case c == d.escape:
+		if err := d.flush(); err != nil {
+			return err
+		}
+		d.escaped, d.escOffset, d.escIndex = true, d.offset, d.index
+	case isDigit(c) && d.hasPending && (!d.hasCount || d.multiDigit):
+		digit := int64(c - '0')
+		if d.count > (math.MaxInt64-digit)/10 {
+			return fmt.Errorf("%w: repeat count at byte offset %d", ErrOutputTooLarge, d.pendingOffset)
+		}
+		d.count, d.hasCount = d.count*10+digit, true
+	case isDigit(c) && !d.lenient:
+		if !d.hasPending {
+			return &ParseError{Index: d.index, Offset: d.offset, Snippet: string(c), Reason: ReasonLeadingDigit}
+		}
+		// the previous rune is the single digit of the count
+		return &ParseError{
+			Index: d.index - 1, Offset: d.offset - 1, Snippet: string([]rune{rune('0' + d.count), c}), Reason: ReasonDoubleDigit,
+		}
+	default:
+		if err := d.flush(); err != nil {
+			return err
+		}
+		d.hold(c, d.offset)
+	}
+	return nil
+}
+
+func (d *decoder) hold(c rune, offset int64) {
+	d.pending, d.pendingOffset, d.hasPending = c, offset, true
+	d.count, d.hasCount = 0, false
+}
+
+// flush writes the pending rune as many times as its count says.
+func (d *decoder) flush() error {
+	if !d.hasPending {
+		return nil
+	}
+	d.hasPending = false
+
+	n := int64(1)
+	if d.hasCount {
+		n = d.count
+	}
+	size := int64(utf8.RuneLen(d.pending))
+	if n > (math.MaxInt64-d.written)/size || d.maxOutput > 0 && d.written+n*size > d.maxOutput {
+		return fmt.Errorf("%w: repeat of %q at byte offset %d", ErrOutputTooLarge, d.pending, d.pendingOffset)
+	}
+
+	for range n {
+		if _, err := d.out.WriteRune(d.pending); err != nil {
+			return err
+		}
+	}
+	d.written += n * size
+	return nil
+}
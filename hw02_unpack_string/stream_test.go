@@ -0,0 +1,77 @@
+package hw02unpackstring
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackStream(t *testing.T) {
+	t.Run("same result as Unpack", func(t *testing.T) {
+		for _, input := range []string{"a4bc2d5e", "", `qwe\45`, `qwe\\5`, "привет2мир0"} {
+			expected, err := Unpack(input)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			// one byte per read splits multibyte runes between reads
+			err = UnpackStream(iotest.OneByteReader(strings.NewReader(input)), &out)
+			require.NoError(t, err)
+			require.Equal(t, expected, out.String())
+		}
+	})
+
+	t.Run("large input", func(t *testing.T) {
+		const repeats = 100_000
+		var out bytes.Buffer
+		err := UnpackStream(strings.NewReader(strings.Repeat("a2б3", repeats)), &out)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("aaббб", repeats), out.String())
+	})
+
+	t.Run("error offsets", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			offset string
+		}{
+			{input: "3abc", offset: "byte offset 0"},
+			{input: "aaa10b", offset: "byte offset 3"},
+			{input: "ыы10", offset: "byte offset 4"},
+			{input: `qw\ne`, offset: "byte offset 2"},
+			{input: `abc\`, offset: "byte offset 3"},
+		}
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.input, func(t *testing.T) {
+				err := UnpackStream(strings.NewReader(tc.input), io.Discard)
+				require.ErrorIs(t, err, ErrInvalidString)
+				require.Contains(t, err.Error(), tc.offset)
+			})
+		}
+	})
+
+	t.Run("invalid UTF-8", func(t *testing.T) {
+		for _, input := range []string{"a\xff2", "ы\xd0", "ab\xc3(", "a\\\xff"} {
+			var out bytes.Buffer
+			err := UnpackStream(strings.NewReader(input), &out)
+			require.ErrorIs(t, err, ErrInvalidString, "input %q", input)
+			require.ErrorIs(t, err, ErrInvalidUTF8, "input %q", input)
+			require.NotContains(t, out.String(), "\uFFFD", "input %q", input)
+		}
+
+		res, err := Unpack("\uFFFD2")
+		require.NoError(t, err, "U+FFFD itself is a valid rune")
+		require.Equal(t, "\uFFFD\uFFFD", res)
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		err := UnpackStream(iotest.ErrReader(errRead), io.Discard)
+		require.ErrorIs(t, err, errRead)
+		require.NotErrorIs(t, err, ErrInvalidString)
+	})
+}
@@ -6,7 +6,10 @@ import (
 
 var ErrInvalidString = errors.New("invalid string")
 
-func Unpack(_ string) (string, error) {
-	// Place your code here.
-	return "", nil
+var defaultUnpacker, _ = NewUnpacker()
+
+// Unpack expands every rune followed by a digit into that many copies of the rune.
+// Digits and backslashes can be used as plain runes when escaped with a backslash.
+func Unpack(input string) (string, error) {
+	return defaultUnpacker.Unpack(input)
 }
@@ -0,0 +1,87 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrOutputTooLarge = errors.New("output size limit exceeded")
+	ErrInvalidOption  = errors.New("invalid unpacker option")
+)
+
+// Unpacker holds the grammar used to decode packed strings.
+// The zero configuration (see NewUnpacker without options) is the one used by Unpack.
+type Unpacker struct {
+	escape     rune
+	multiDigit bool
+	lenient    bool
+	maxOutput  int64 // in bytes, 0 means no limit
+}
+
+const escapeRune = '\\'
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+type Option func(u *Unpacker) error
+
+// WithEscape sets the rune used to escape digits and itself, backslash by default.
+func WithEscape(r rune) Option {
+	return func(u *Unpacker) error {
+		if isDigit(r) {
+			return fmt.Errorf("%w: escape rune can't be a digit", ErrInvalidOption)
+		}
+		u.escape = r
+		return nil
+	}
+}
+
+// WithMultiDigitCounts allows repeat counts longer than one digit, e.g. "a12".
+func WithMultiDigitCounts() Option {
+	return func(u *Unpacker) error {
+		u.multiDigit = true
+		return nil
+	}
+}
+
+// WithMaxOutputSize limits the decoded output to n bytes, which guards against expansion bombs like "a9999999".
+func WithMaxOutputSize(n int64) Option {
+	return func(u *Unpacker) error {
+		if n < 0 {
+			return fmt.Errorf("%w: max output size must be >= 0", ErrInvalidOption)
+		}
+		u.maxOutput = n
+		return nil
+	}
+}
+
+// WithLenient turns malformed sequences into plain runes instead of errors:
+// a digit with nothing to repeat and an escape rune that doesn't escape anything are treated as plain runes.
+func WithLenient() Option {
+	return func(u *Unpacker) error {
+		u.lenient = true
+		return nil
+	}
+}
+
+func NewUnpacker(opts ...Option) (*Unpacker, error) {
+	u := &Unpacker{escape: escapeRune}
+	for _, opt := range opts {
+		if err := opt(u); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func (u *Unpacker) Unpack(input string) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(input))
+	if err := u.UnpackStream(strings.NewReader(input), &sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
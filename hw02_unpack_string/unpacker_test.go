@@ -0,0 +1,77 @@
+package hw02unpackstring
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpacker(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		input    string
+		expected string
+	}{
+		{name: "default", input: `a4bc2d5e\3`, expected: "aaaabccddddde3"},
+		{name: "multi-digit", opts: []Option{WithMultiDigitCounts()}, input: "a12b", expected: "aaaaaaaaaaaab"},
+		{name: "multi-digit zero", opts: []Option{WithMultiDigitCounts()}, input: "a00b01", expected: "b"},
+		{name: "multi-digit escaped", opts: []Option{WithMultiDigitCounts()}, input: `\110`, expected: "1111111111"},
+		{name: "custom escape", opts: []Option{WithEscape('/')}, input: `/4/5//2\`, expected: `45//\`},
+		{name: "lenient leading digit", opts: []Option{WithLenient()}, input: "3abc", expected: "3abc"},
+		{name: "lenient double digit", opts: []Option{WithLenient()}, input: "aaa10b", expected: "aaa0b"},
+		{name: "lenient bad escape", opts: []Option{WithLenient()}, input: `qw\ne\`, expected: `qw\ne\`},
+		{name: "size limit not reached", opts: []Option{WithMaxOutputSize(4)}, input: "a2ы", expected: "aaы"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := NewUnpacker(tc.opts...)
+			require.NoError(t, err)
+			result, err := u.Unpack(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUnpackerErrors(t *testing.T) {
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewUnpacker(WithEscape('7'))
+		require.ErrorIs(t, err, ErrInvalidOption)
+		_, err = NewUnpacker(WithMaxOutputSize(-1))
+		require.ErrorIs(t, err, ErrInvalidOption)
+	})
+
+	t.Run("strict custom escape", func(t *testing.T) {
+		u, err := NewUnpacker(WithEscape('/'))
+		require.NoError(t, err)
+		_, err = u.Unpack(`/a`)
+		require.ErrorIs(t, err, ErrInvalidString)
+	})
+
+	t.Run("expansion bomb", func(t *testing.T) {
+		u, err := NewUnpacker(WithMultiDigitCounts(), WithMaxOutputSize(1<<20))
+		require.NoError(t, err)
+		err = u.UnpackStream(strings.NewReader("ab9999999999c"), io.Discard)
+		require.ErrorIs(t, err, ErrOutputTooLarge)
+		require.Contains(t, err.Error(), "byte offset 1")
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		u, err := NewUnpacker(WithMaxOutputSize(4))
+		require.NoError(t, err)
+		_, err = u.Unpack("a2ы2")
+		require.ErrorIs(t, err, ErrOutputTooLarge)
+	})
+
+	t.Run("count overflow", func(t *testing.T) {
+		u, err := NewUnpacker(WithMultiDigitCounts())
+		require.NoError(t, err)
+		_, err = u.Unpack("a99999999999999999999")
+		require.ErrorIs(t, err, ErrOutputTooLarge)
+	})
+}
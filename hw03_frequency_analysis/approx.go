@@ -0,0 +1,140 @@
+package hw03frequencyanalysis
+
+import (
+	"container/heap"
+)
+
+// spaceSaving is the approximate tally by Metwally, Agrawal and El Abbadi,
+// "Efficient Computation of Frequent and Top-k Elements in Data Streams".
+// It monitors capacity words, a new word replaces the least counted one and inherits its count,
+// so counts can only be overestimated and the overestimation never exceeds the number
+// of added words and n-grams divided by capacity.
+type spaceSaving struct {
+	capacity int
+
+	entries monitoredHeap
+
+	lookup map[string]*monitored
+
+	total int // words counted, see countWord
+}
+
+type monitored struct {
+	word string
+
+	count int
+
+	index int // position in monitoredHeap
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{
+		capacity: capacity,
+		entries:  make(monitoredHeap, 0, capacity),
+		lookup:   make(map[string]*monitored, capacity),
+	}
+}
+
+func (s *spaceSaving) add(word string) {
+	if m := s.lookup[word]; m != nil {
+		m.count++
+		heap.Fix(&s.entries, m.index)
+		return
+	}
+
+	if len(s.entries) < s.capacity {
+		m := &monitored{word: word, count: 1}
+		heap.Push(&s.entries, m)
+		s.lookup[word] = m
+		return
+	}
+
+	m := s.entries[0]
+	delete(s.lookup, m.word)
+	m.word = word
+	m.count++
+	s.lookup[word] = m
+	heap.Fix(&s.entries, 0)
+}
+
+func (s *spaceSaving) top(n int) []WordCount {
+	counts := make(map[string]int, len(s.entries))
+
+	for _, m := range s.entries {
+		counts[m.word] = m.count
+	}
+
+	return selectTop(counts, n)
+}
+
+func (s *spaceSaving) countWord() {
+	s.total++
+}
+
+func (s *spaceSaving) totalWords() int {
+	return s.total
+}
+
+// floor is the highest possible count of a word that is not monitored.
+func (s *spaceSaving) floor() int {
+	if len(s.entries) < s.capacity {
+		return 0
+	}
+	return s.entries[0].count
+}
+
+// merge sums counts of both summaries and keeps capacity highest of them. A word missing in one summary
+// gets its floor, so merged counts are still upper bounds of the true ones.
+func (s *spaceSaving) merge(other tally) {
+	o := other.(*spaceSaving)
+
+	floorS, floorO := s.floor(), o.floor()
+	counts := make(map[string]int, len(s.entries)+len(o.entries))
+
+	for _, m := range s.entries {
+		counts[m.word] = m.count + floorO
+	}
+	for _, m := range o.entries {
+		if count, ok := counts[m.word]; ok {
+			counts[m.word] = count - floorO + m.count
+		} else {
+			counts[m.word] = m.count + floorS
+		}
+	}
+
+	s.total += o.total
+	s.entries = s.entries[:0]
+	clear(s.lookup)
+
+	for _, wc := range selectTop(counts, s.capacity) {
+		m := &monitored{word: wc.Word, count: wc.Count}
+		heap.Push(&s.entries, m)
+		s.lookup[wc.Word] = m
+	}
+}
+
+// monitoredHeap is a min-heap by count, its root is the word to be replaced next.
+type monitoredHeap []*monitored
+
+func (h monitoredHeap) Len() int { return len(h) }
+
+func (h monitoredHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h monitoredHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *monitoredHeap) Push(x any) {
+	m := x.(*monitored)
+	m.index = len(*h)
+	*h = append(*h, m)
+}
+
+func (h *monitoredHeap) Pop() any {
+	old := *h
+	m := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return m
+}
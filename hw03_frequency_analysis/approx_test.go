@@ -0,0 +1,59 @@
+package hw03frequencyanalysis
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproximation(t *testing.T) {
+	t.Run("enough capacity is exact", func(t *testing.T) {
+		expected, expectedTotal := TopCounts(text, 10)
+		counts, total := TopCounts(text, 10, WithApproximation(1000))
+		require.Equal(t, expected, counts)
+		require.Equal(t, expectedTotal, total)
+	})
+
+	t.Run("zero capacity is exact", func(t *testing.T) {
+		require.Equal(t, Top10(text), TopN(text, 10, WithApproximation(0)))
+	})
+
+	t.Run("error bounds", func(t *testing.T) {
+		const capacity = 50
+		rnd := rand.New(rand.NewSource(1))
+		zipf := rand.NewZipf(rnd, 1.2, 1, 10_000)
+		var sb strings.Builder
+		for range 100_000 {
+			sb.WriteString("w" + strconv.FormatUint(zipf.Uint64(), 10) + " ")
+		}
+
+		exact, total := TopCounts(sb.String(), 10_000)
+		trueCounts := make(map[string]int, len(exact))
+		for _, wc := range exact {
+			trueCounts[wc.Word] = wc.Count
+		}
+
+		c := NewCounter(WithApproximation(capacity))
+		c.Add(sb.String())
+		c.Flush()
+		require.Equal(t, total, c.Total())
+
+		approx := c.Top(capacity * 2)
+		require.Len(t, approx, capacity, "no more than capacity words are monitored")
+		reported := make(map[string]bool, len(approx))
+		for _, wc := range approx {
+			reported[wc.Word] = true
+			require.GreaterOrEqual(t, wc.Count, trueCounts[wc.Word], wc.Word)
+			require.LessOrEqual(t, wc.Count, trueCounts[wc.Word]+total/capacity, wc.Word)
+		}
+		for word, count := range trueCounts {
+			if count > total/capacity {
+				require.True(t, reported[word], "heavy hitter %s is lost", word)
+			}
+		}
+		require.Equal(t, exact[0].Word, approx[0].Word)
+	})
+}
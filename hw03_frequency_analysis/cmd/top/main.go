@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	hw03frequencyanalysis "github.com/vadim-ktnkv/glang-ots-pr/hw03_frequency_analysis"
+)
+
+var (
+	top, workers, approx                     int
+	ngrams                                   string
+	counts, unicodeWords, nfc, caseSensitive bool
+)
+
+func init() {
+	flag.IntVar(&top, "n", 10, "number of most frequent words to print")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "number of files counted in parallel")
+	flag.IntVar(&approx, "approx", 0, "count approximately keeping at most this many words in memory")
+	flag.StringVar(&ngrams, "ngrams", "1", "comma separated sizes of counted word sequences, e.g. 1,2")
+	flag.BoolVar(&counts, "counts", false, "print counts next to words")
+	flag.BoolVar(&unicodeWords, "unicode", false, "split words by unicode categories instead of whitespace")
+	flag.BoolVar(&nfc, "nfc", false, "normalize text to unicode NFC")
+	flag.BoolVar(&caseSensitive, "case-sensitive", false, "count words with different case separately")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\nReads stdin if no files are given.\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	opts, err := options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var topCounts []hw03frequencyanalysis.WordCount
+	if flag.NArg() == 0 {
+		c := hw03frequencyanalysis.NewCounter(opts...)
+		err = c.AddReader(os.Stdin)
+		topCounts = c.Top(top)
+	} else {
+		topCounts, _, err = hw03frequencyanalysis.TopCountsFiles(flag.Args(), top, workers, opts...)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for _, wc := range topCounts {
+		if counts {
+			fmt.Printf("%s\t%d\n", wc.Word, wc.Count)
+		} else {
+			fmt.Println(wc.Word)
+		}
+	}
+}
+
+func options() ([]hw03frequencyanalysis.Option, error) {
+	var opts []hw03frequencyanalysis.Option
+
+	sizes := make([]int, 0, 3)
+	for _, s := range strings.Split(ngrams, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid n-gram size %q", s)
+		}
+		sizes = append(sizes, n)
+	}
+	opts = append(opts, hw03frequencyanalysis.WithNGrams(sizes...))
+
+	if unicodeWords {
+		opts = append(opts, hw03frequencyanalysis.WithTokenizer(hw03frequencyanalysis.UnicodeWords))
+	}
+	if nfc {
+		opts = append(opts, hw03frequencyanalysis.WithNFC())
+	}
+	if caseSensitive {
+		opts = append(opts, hw03frequencyanalysis.WithCaseSensitive())
+	}
+	if approx > 0 {
+		opts = append(opts, hw03frequencyanalysis.WithApproximation(approx))
+	}
+	return opts, nil
+}
@@ -0,0 +1,100 @@
+package hw03frequencyanalysis
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const readChunkSize = 32 * 1024
+
+// Counter counts words of unbounded input fed in chunks, Top can be asked at any time.
+// Chunks are cut at the last whitespace and the unfinished word is held back until the next chunk,
+// so a custom tokenizer must treat whitespace as a separator.
+// Counter is safe for concurrent use, but concurrent Add calls interleave their chunks in random order.
+type Counter struct {
+	mu sync.Mutex
+
+	cfg config
+
+	freq tally
+
+	tail strings.Builder // unfinished word from the end of the previous chunks
+
+	prev []string // last counted words for n-grams
+}
+
+func NewCounter(opts ...Option) *Counter {
+	cfg := newConfig(opts)
+	return &Counter{cfg: cfg, freq: cfg.newTally()}
+}
+
+// Add counts the words of the next chunk of input.
+func (c *Counter) Add(chunk string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// only the new chunk is searched, so a long word fed in many chunks is not rescanned every time
+	cut := strings.LastIndexFunc(chunk, unicode.IsSpace)
+	if cut < 0 {
+		c.tail.WriteString(chunk)
+		return
+	}
+	// whitespace itself doesn't matter, only the word after it is held back
+	_, size := utf8.DecodeRuneInString(chunk[cut:])
+	cut += size
+
+	txt := chunk[:cut]
+	if c.tail.Len() > 0 {
+		txt = c.tail.String() + txt
+		c.tail.Reset()
+	}
+	c.tail.WriteString(chunk[cut:])
+	c.prev = c.cfg.count(txt, c.freq, c.prev)
+}
+
+// AddReader counts all words from r, the end of r is treated as the end of a word.
+func (c *Counter) AddReader(r io.Reader) error {
+	buf := make([]byte, readChunkSize)
+
+	for {
+		n, err := r.Read(buf)
+		c.Add(string(buf[:n]))
+
+		if errors.Is(err, io.EOF) {
+			c.Flush()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Flush counts the held back unfinished word, call it when input is over.
+func (c *Counter) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prev = c.cfg.count(c.tail.String(), c.freq, c.prev)
+	c.tail.Reset()
+}
+
+// Top returns up to n most frequent words counted so far, a word held back by Add is not included.
+func (c *Counter) Top(n int) []WordCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.freq.top(n)
+}
+
+// Total returns the number of words counted so far, n-grams are not added to it.
+func (c *Counter) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.freq.totalWords()
+}
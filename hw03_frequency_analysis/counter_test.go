@@ -0,0 +1,86 @@
+package hw03frequencyanalysis
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	expected, expectedTotal := TopCounts(text, 10)
+
+	t.Run("words split between chunks", func(t *testing.T) {
+		for _, chunkSize := range []int{1, 2, 3, 7, 100, len(text)} {
+			c := NewCounter()
+			for start := 0; start < len(text); start += chunkSize {
+				c.Add(text[start:min(start+chunkSize, len(text))])
+			}
+			c.Flush()
+			require.Equal(t, expected, c.Top(10), "chunk size %d", chunkSize)
+			require.Equal(t, expectedTotal, c.Total(), "chunk size %d", chunkSize)
+		}
+	})
+
+	t.Run("top at any time", func(t *testing.T) {
+		c := NewCounter()
+		c.Add("dog cat ca")
+		require.Equal(t, []WordCount{{"cat", 1}, {"dog", 1}}, c.Top(5))
+		c.Add("t dog")
+		require.Equal(t, []WordCount{{"cat", 2}, {"dog", 1}}, c.Top(5))
+		c.Flush()
+		require.Equal(t, []WordCount{{"cat", 2}, {"dog", 2}}, c.Top(5))
+		require.Equal(t, 4, c.Total())
+		require.Len(t, c.Top(0), 0)
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		c := NewCounter(WithStopWords("-", "а"))
+		require.NoError(t, c.AddReader(iotest.HalfReader(strings.NewReader(text))))
+		expected, _ := TopCounts(text, 10, WithStopWords("-", "а"))
+		require.Equal(t, expected, c.Top(10))
+	})
+
+	t.Run("long run without whitespace", func(t *testing.T) {
+		// the unfinished word grows with every chunk, it must not be copied every time
+		long := strings.Repeat("a", 8<<20)
+		c := NewCounter()
+		require.NoError(t, c.AddReader(strings.NewReader("b "+long+" b "+long)))
+		require.Equal(t, []WordCount{{long, 2}, {"b", 2}}, c.Top(10))
+		require.Equal(t, 4, c.Total())
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		c := NewCounter()
+		err := c.AddReader(iotest.ErrReader(errRead))
+		require.ErrorIs(t, err, errRead)
+	})
+
+	t.Run("concurrent chunks", func(t *testing.T) {
+		c := NewCounter()
+		wg := sync.WaitGroup{}
+		wg.Add(10)
+		for range 10 {
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					c.Add("a b a ")
+					c.Top(1)
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, []WordCount{{"a", 2000}, {"b", 1000}}, c.Top(10))
+	})
+}
+
+func TestSelectTop(t *testing.T) {
+	counts := map[string]int{"e": 1, "d": 2, "c": 2, "b": 3, "a": 1}
+	require.Equal(t, []WordCount{{"b", 3}, {"c", 2}, {"d", 2}}, selectTop(counts, 3))
+	require.Equal(t, []WordCount{{"b", 3}, {"c", 2}, {"d", 2}, {"a", 1}, {"e", 1}}, selectTop(counts, 10))
+	require.Len(t, selectTop(counts, -1), 0)
+}
@@ -0,0 +1,49 @@
+package hw03frequencyanalysis
+
+import (
+	"container/heap"
+)
+
+// wordHeap is a min-heap in wordsCompare order, its root is the weakest of the selected words.
+type wordHeap []item
+
+func (h wordHeap) Len() int { return len(h) }
+
+func (h wordHeap) Less(i, j int) bool { return wordsCompare(&h[i], &h[j]) > 0 }
+
+func (h wordHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *wordHeap) Push(x any) { *h = append(*h, x.(item)) }
+
+func (h *wordHeap) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// selectTop picks n strongest words without sorting all of them, it takes O(m*log(n)) for m distinct words.
+func selectTop(counts map[string]int, n int) []WordCount {
+	h := make(wordHeap, 0, max(min(n, len(counts)), 0))
+
+	for word, count := range counts {
+		it := item{word, count}
+
+		switch {
+		case len(h) < n:
+			heap.Push(&h, it)
+		case n > 0 && wordsCompare(&it, &h[0]) < 0:
+			h[0] = it
+			heap.Fix(&h, 0)
+		}
+	}
+
+	topCounts := make([]WordCount, len(h))
+
+	for i := len(topCounts) - 1; i >= 0; i-- {
+		it := heap.Pop(&h).(item)
+		topCounts[i] = WordCount{it.word, it.count}
+	}
+
+	return topCounts
+}
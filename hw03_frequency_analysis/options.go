@@ -0,0 +1,178 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const defaultPunctuation = "!\":;',.`"
+
+// Tokenizer splits text into raw words.
+type Tokenizer func(txt string) []string
+
+// Normalizer converts a raw word into the form it is counted by, an empty result drops the word.
+type Normalizer func(word string) string
+
+type config struct {
+	tokenizer     Tokenizer
+	normalizer    Normalizer
+	punctuation   string
+	stopWords     map[string]struct{}
+	caseSensitive bool
+	approxWords   int // capacity of the approximate tally, 0 means exact counting
+	nfc           bool
+	ngrams        []int
+}
+
+type Option func(c *config)
+
+func newConfig(opts []Option) config {
+	c := config{
+		tokenizer:   strings.Fields,
+		punctuation: defaultPunctuation,
+		stopWords:   map[string]struct{}{"-": {}},
+		ngrams:      []int{1},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithTokenizer replaces strings.Fields used to split text into words.
+func WithTokenizer(t Tokenizer) Option {
+	return func(c *config) {
+		c.tokenizer = t
+	}
+}
+
+// WithNormalizer adds a step after punctuation trimming and lower-casing.
+func WithNormalizer(n Normalizer) Option {
+	return func(c *config) {
+		c.normalizer = n
+	}
+}
+
+// WithPunctuation sets the runes trimmed from both ends of a word, an empty set disables trimming.
+func WithPunctuation(set string) Option {
+	return func(c *config) {
+		c.punctuation = set
+	}
+}
+
+// WithStopWords replaces the default stop-word list ("-"), stop words are matched after normalization.
+func WithStopWords(words ...string) Option {
+	return func(c *config) {
+		c.stopWords = make(map[string]struct{}, len(words))
+		for _, w := range words {
+			c.stopWords[w] = struct{}{}
+		}
+	}
+}
+
+// WithCaseSensitive keeps "Нога" and "нога" as different words.
+func WithCaseSensitive() Option {
+	return func(c *config) {
+		c.caseSensitive = true
+	}
+}
+
+// WithApproximation counts words approximately with the Space-Saving algorithm that keeps
+// at most capacity words in memory, whatever the number of distinct words is.
+// With bound = number of added words and n-grams / capacity, every reported count is at most bound
+// higher than the true one, and every word or n-gram that occurs more than bound times is guaranteed
+// to be reported. Without WithNGrams the bound is the total of counted words divided by capacity.
+// A capacity <= 0 keeps exact counting.
+func WithApproximation(capacity int) Option {
+	return func(c *config) {
+		c.approxWords = max(capacity, 0)
+	}
+}
+
+// WithNFC brings text to Unicode normalization form C before tokenizing,
+// so a letter with a combining mark and the same precomposed letter are one word.
+func WithNFC() Option {
+	return func(c *config) {
+		c.nfc = true
+	}
+}
+
+// WithNGrams sets the sizes of counted word sequences, e.g. WithNGrams(1, 2) counts words and bigrams.
+// Words of an n-gram are joined with a space, stop words are removed before n-grams are built.
+// The total number of counted words stays the number of words, n-grams don't add to it.
+// Sizes <= 0 are ignored, the default is single words only.
+func WithNGrams(sizes ...int) Option {
+	return func(c *config) {
+		c.ngrams = c.ngrams[:0:0]
+		for _, n := range sizes {
+			if n > 0 {
+				c.ngrams = append(c.ngrams, n)
+			}
+		}
+	}
+}
+
+// history returns the number of previous words needed to build n-grams across chunks.
+func (c *config) history() int {
+	longest := 0
+	for _, n := range c.ngrams {
+		longest = max(longest, n)
+	}
+	return max(longest-1, 0)
+}
+
+func (c *config) newTally() tally {
+	if c.approxWords > 0 {
+		return newSpaceSaving(c.approxWords)
+	}
+	return newFrequencies()
+}
+
+// normalize returns the counted form of a raw word and false if the word must be skipped.
+func (c *config) normalize(word string) (string, bool) {
+	if c.punctuation != "" {
+		word = strings.Trim(word, c.punctuation)
+	}
+	if !c.caseSensitive {
+		word = strings.ToLower(word)
+	}
+	if c.normalizer != nil {
+		word = c.normalizer(word)
+	}
+	if word == "" {
+		return "", false
+	}
+	if _, ok := c.stopWords[word]; ok {
+		return "", false
+	}
+	return word, true
+}
+
+// count adds every counted word and n-gram of txt to freq. prev holds the last words of
+// the previous text, so n-grams continue across chunks, the last words of txt are returned for the next call.
+func (c *config) count(txt string, freq tally, prev []string) []string {
+	if c.nfc {
+		txt = norm.NFC.String(txt)
+	}
+
+	for _, w := range c.tokenizer(txt) {
+		word, ok := c.normalize(w)
+		if !ok {
+			continue
+		}
+
+		freq.countWord()
+		prev = append(prev, word)
+		for _, n := range c.ngrams {
+			if n <= len(prev) {
+				freq.add(strings.Join(prev[len(prev)-n:], " "))
+			}
+		}
+		if len(prev) > c.history() {
+			prev = prev[len(prev)-c.history():]
+		}
+	}
+
+	return prev
+}
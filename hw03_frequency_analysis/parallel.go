@@ -0,0 +1,84 @@
+package hw03frequencyanalysis
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+var ErrWorkersCountLow = errors.New("workers count must be >0")
+
+// TopCountsFiles counts words of many files on parallel workers. Files are shared between workers,
+// every worker counts into its own tally and the tallies are merged before the top n is selected.
+// The end of a file ends a word and an n-gram. With exact counting the result doesn't depend on
+// the number of workers, for single words it is the same as TopCounts of all files joined by newlines.
+func (a *Analyzer) TopCountsFiles(paths []string, n, workers int) ([]WordCount, int, error) {
+	if workers <= 0 {
+		return nil, 0, ErrWorkersCountLow
+	}
+
+	pathsQueue := make(chan string)
+	closeSignal := make(chan struct{})
+	closeOnce := sync.Once{}
+
+	go func() {
+		defer close(pathsQueue)
+
+		for _, path := range paths {
+			select {
+			case <-closeSignal:
+				return
+			case pathsQueue <- path:
+			}
+		}
+	}()
+
+	tallies := make([]tally, workers)
+	errs := make([]error, workers)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+
+	for i := range workers {
+		tallies[i] = a.cfg.newTally()
+
+		go func() {
+			defer wg.Done()
+
+			for path := range pathsQueue {
+				if err := a.countFile(path, tallies[i]); err != nil {
+					errs[i] = err
+					closeOnce.Do(func() { close(closeSignal) })
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, 0, err
+	}
+
+	merged := tallies[0]
+	for _, t := range tallies[1:] {
+		merged.merge(t)
+	}
+
+	return merged.top(n), merged.totalWords(), nil
+}
+
+func (a *Analyzer) countFile(path string, freq tally) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	c := Counter{cfg: a.cfg, freq: freq}
+
+	return c.AddReader(f)
+}
+
+func TopCountsFiles(paths []string, n, workers int, opts ...Option) ([]WordCount, int, error) {
+	return NewAnalyzer(opts...).TopCountsFiles(paths, n, workers)
+}
@@ -0,0 +1,112 @@
+package hw03frequencyanalysis
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, texts []string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, 0, len(texts))
+	for i, txt := range texts {
+		path := filepath.Join(dir, strconv.Itoa(i)+".txt")
+		require.NoError(t, os.WriteFile(path, []byte(txt), 0o600))
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestTopCountsFiles(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	fields := strings.Fields(text)
+	texts := make([]string, 200)
+	for i := range texts {
+		words := make([]string, rnd.Intn(50))
+		for j := range words {
+			words[j] = fields[rnd.Intn(len(fields))]
+		}
+		texts[i] = strings.Join(words, " ")
+	}
+	paths := writeFiles(t, texts)
+
+	t.Run("same as single-threaded", func(t *testing.T) {
+		for _, opts := range [][]Option{nil, {WithTokenizer(UnicodeWords), WithCaseSensitive()}} {
+			expected, expectedTotal := TopCounts(strings.Join(texts, "\n"), 20, opts...)
+			for _, workers := range []int{1, 3, 16} {
+				counts, total, err := TopCountsFiles(paths, 20, workers, opts...)
+				require.NoError(t, err)
+				require.Equal(t, expected, counts, "%d workers", workers)
+				require.Equal(t, expectedTotal, total, "%d workers", workers)
+			}
+		}
+		require.Equal(t, Top10(strings.Join(texts, "\n")), must(TopCountsFiles(paths, 10, 4)))
+	})
+
+	t.Run("n-grams don't cross files", func(t *testing.T) {
+		expected := map[string]int{}
+		expectedTotal := 0
+		for _, txt := range texts {
+			counts, total := TopCounts(txt, 1000, WithNGrams(2))
+			expectedTotal += total
+			for _, wc := range counts {
+				expected[wc.Word] += wc.Count
+			}
+		}
+
+		counts, total, err := TopCountsFiles(paths, 10, 5, WithNGrams(2))
+		require.NoError(t, err)
+		require.Equal(t, expectedTotal, total)
+		require.Equal(t, selectTop(expected, 10), counts)
+	})
+
+	t.Run("approximate merge keeps upper bounds", func(t *testing.T) {
+		const capacity = 30
+		exact, total := TopCounts(strings.Join(texts, "\n"), 1000)
+		trueCounts := make(map[string]int, len(exact))
+		for _, wc := range exact {
+			trueCounts[wc.Word] = wc.Count
+		}
+
+		counts, approxTotal, err := TopCountsFiles(paths, capacity, 8, WithApproximation(capacity))
+		require.NoError(t, err)
+		require.Equal(t, total, approxTotal)
+		require.Len(t, counts, capacity)
+		for _, wc := range counts {
+			require.GreaterOrEqual(t, wc.Count, trueCounts[wc.Word], wc.Word)
+		}
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		counts, total, err := TopCountsFiles(nil, 10, 4)
+		require.NoError(t, err)
+		require.Len(t, counts, 0)
+		require.Equal(t, 0, total)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := TopCountsFiles(paths, 10, 0)
+		require.ErrorIs(t, err, ErrWorkersCountLow)
+
+		withMissing := append([]string{filepath.Join(t.TempDir(), "missing.txt")}, paths...)
+		_, _, err = TopCountsFiles(withMissing, 10, 4)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func must(counts []WordCount, _ int, err error) []string {
+	if err != nil {
+		panic(err)
+	}
+	words := make([]string, 0, len(counts))
+	for _, wc := range counts {
+		words = append(words, wc.Word)
+	}
+	return words
+}
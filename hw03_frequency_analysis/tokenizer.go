@@ -0,0 +1,54 @@
+package hw03frequencyanalysis
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// isWordRune reports if r is a letter, a digit or a combining mark.
+func isWordRune(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M, unicode.N)
+}
+
+// isJoiner reports if r joins two word parts into one word like "какой-то" or "don't".
+// Dashes like "—" and "–" are not joiners, they separate words.
+func isJoiner(r rune) bool {
+	switch r {
+	case '-', '‐', '‑', '\'', '’':
+		return true
+	}
+	return false
+}
+
+// UnicodeWords is a Tokenizer that splits text by unicode categories instead of whitespace:
+// a word is a run of letters, digits and marks, hyphens and apostrophes are kept only inside a word.
+// "слово—слово" gives two words, "«цитата»" gives "цитата", "Винни-Пух" and "don't" stay single words.
+func UnicodeWords(txt string) []string {
+	var words []string
+
+	start := -1 // start of the current word, -1 if outside a word
+
+	for i, r := range txt {
+		switch {
+		case isWordRune(r):
+			if start < 0 {
+				start = i
+			}
+		case start >= 0 && isJoiner(r):
+			next, _ := utf8.DecodeRuneInString(txt[i+utf8.RuneLen(r):])
+			if !isWordRune(next) {
+				words = append(words, txt[start:i])
+				start = -1
+			}
+		case start >= 0:
+			words = append(words, txt[start:i])
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, txt[start:])
+	}
+
+	return words
+}
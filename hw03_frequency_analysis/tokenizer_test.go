@@ -0,0 +1,98 @@
+package hw03frequencyanalysis
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnicodeWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "", expected: nil},
+		{input: " - — ... ", expected: nil},
+		{input: "слово—слово", expected: []string{"слово", "слово"}},
+		{input: "«цитата»", expected: []string{"цитата"}},
+		{input: "какой-то какойто", expected: []string{"какой-то", "какойто"}},
+		{input: "-край- дефис--двойной", expected: []string{"край", "дефис", "двойной"}},
+		{input: "don't rock’n’roll 'quoted'", expected: []string{"don't", "rock’n’roll", "quoted"}},
+		{input: "dog,cat dog...cat 2024-й", expected: []string{"dog", "cat", "dog", "cat", "2024-й"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, UnicodeWords(tc.input))
+		})
+	}
+}
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestUnicodeFixtures(t *testing.T) {
+	t.Run("english", func(t *testing.T) {
+		txt := readFixture(t, "testdata/en.txt")
+
+		counts, total := TopCounts(txt, 8, WithTokenizer(UnicodeWords))
+		expected := []WordCount{
+			{"the", 6}, {"guide", 4}, {"says", 3}, {"a", 2}, {"don't", 2}, {"panic", 2}, {"towel", 2}, {"well-known", 2},
+		}
+		require.Equal(t, expected, counts)
+		require.Equal(t, 37, total)
+
+		bigrams, total := TopCounts(txt, 3, WithTokenizer(UnicodeWords), WithNGrams(2))
+		require.Equal(t, []WordCount{{"don't panic", 2}, {"guide says", 2}, {"the guide", 2}}, bigrams)
+		require.Equal(t, 37, total, "total counts words, not bigrams")
+
+		_, total = TopCounts(txt, 3, WithTokenizer(UnicodeWords), WithNGrams(1, 2, 3))
+		require.Equal(t, 37, total, "total does not depend on n-gram sizes")
+	})
+
+	t.Run("russian", func(t *testing.T) {
+		txt := readFixture(t, "testdata/ru.txt")
+
+		counts, total := TopCounts(txt, 7, WithTokenizer(UnicodeWords), WithNFC())
+		expected := []WordCount{
+			{"мёд", 7}, {"винни-пух", 2}, {"какой-то", 2}, {"любит", 2}, {"медведь", 2}, {"слово", 2}, {"это", 2},
+		}
+		require.Equal(t, expected, counts)
+		require.Equal(t, 29, total)
+
+		ngrams := TopN(txt, 3, WithTokenizer(UnicodeWords), WithNGrams(1, 2, 3))
+		require.Equal(t, []string{"мёд", "винни-пух", "какой-то"}, ngrams)
+		bigrams := TopN(txt, 1, WithTokenizer(UnicodeWords), WithNGrams(2))
+		require.Equal(t, []string{"мёд мёд"}, bigrams)
+	})
+}
+
+func TestNFC(t *testing.T) {
+	txt := "ме\u0308д мёд" // decomposed and precomposed "ё"
+
+	counts, _ := TopCounts(txt, 5, WithNFC())
+	require.Equal(t, []WordCount{{"мёд", 2}}, counts)
+
+	counts, _ = TopCounts(txt, 5)
+	require.Len(t, counts, 2, "without NFC the forms differ")
+}
+
+func TestNGramsAcrossChunks(t *testing.T) {
+	expected, expectedTotal := TopCounts(text, 10, WithNGrams(1, 2, 3))
+
+	c := NewCounter(WithNGrams(1, 2, 3))
+	for start := 0; start < len(text); start += 13 {
+		c.Add(text[start:min(start+13, len(text))])
+	}
+	c.Flush()
+	require.Equal(t, expected, c.Top(10))
+	require.Equal(t, expectedTotal, c.Total())
+
+	require.Equal(t, []string{"a b", "b c"}, TopN("a - b c", 5, WithNGrams(2, 0, -1)))
+}
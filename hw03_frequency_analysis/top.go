@@ -1,7 +1,6 @@
 package hw03frequencyanalysis
 
 import (
-	"slices"
 	"strings"
 )
 
@@ -19,46 +18,102 @@ func wordsCompare(a *item, b *item) int {
 	return b.count - a.count
 }
 
-var words []*item
+// WordCount is a word with the number of its occurrences in text.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// tally accumulates counted words, it is never shared between calls.
+type tally interface {
+	add(word string) // word is a single word or an n-gram
+	countWord()      // counts a word of the text in the total, whatever n-grams are built of it
+	top(n int) []WordCount
+	totalWords() int
+	merge(other tally) // other is a tally of the same kind
+}
 
-var wordsLookup map[string]*item
+// frequencies is the exact tally.
+type frequencies struct {
+	counts map[string]int
 
-func Top10(txt string) []string {
-	words = []*item{}
+	total int
+}
+
+func newFrequencies() *frequencies {
+	return &frequencies{counts: map[string]int{}}
+}
 
-	wordsLookup = map[string]*item{}
+func (f *frequencies) add(word string) {
+	f.counts[word]++
+}
 
-	for _, w := range strings.Fields(txt) {
-		word := strings.ToLower(strings.Trim(w, "!\":;',.`"))
+func (f *frequencies) countWord() {
+	f.total++
+}
 
-		if word == "-" {
-			continue
-		}
+func (f *frequencies) top(n int) []WordCount {
+	return selectTop(f.counts, n)
+}
 
-		itemPtr := wordsLookup[word]
+func (f *frequencies) totalWords() int {
+	return f.total
+}
 
-		if itemPtr == nil {
-			wd := item{word, 1}
+func (f *frequencies) merge(other tally) {
+	o := other.(*frequencies)
 
-			words = append(words, &wd)
+	f.total += o.total
 
-			wordsLookup[word] = &wd
-		} else {
-			itemPtr.count++
-		}
+	for word, count := range o.counts {
+		f.counts[word] += count
 	}
+}
 
-	slices.SortStableFunc(words, wordsCompare)
+// Analyzer counts words with a fixed set of options.
+// It keeps no state between calls, so one Analyzer can be used from many goroutines.
+type Analyzer struct {
+	cfg config
+}
 
-	top10words := make([]string, 0, 10)
+func NewAnalyzer(opts ...Option) *Analyzer {
+	return &Analyzer{cfg: newConfig(opts)}
+}
 
-	for i, wd := range words {
-		top10words = append(top10words, wd.word)
+var defaultAnalyzer = NewAnalyzer()
 
-		if i >= 9 {
-			break
-		}
+// TopCounts returns up to n most frequent words with their counts and the total number of counted words.
+// The total counts every word of txt left after normalization once, n-grams built of it are not added.
+// Words with the same frequency are sorted lexicographically.
+func (a *Analyzer) TopCounts(txt string, n int) ([]WordCount, int) {
+	freq := a.cfg.newTally()
+
+	a.cfg.count(txt, freq, nil)
+
+	return freq.top(n), freq.totalWords()
+}
+
+// TopN returns up to n most frequent words, words with the same frequency are sorted lexicographically.
+func (a *Analyzer) TopN(txt string, n int) []string {
+	topCounts, _ := a.TopCounts(txt, n)
+
+	topWords := make([]string, 0, len(topCounts))
+
+	for _, wc := range topCounts {
+		topWords = append(topWords, wc.Word)
 	}
 
-	return top10words
+	return topWords
+}
+
+func TopCounts(txt string, n int, opts ...Option) ([]WordCount, int) {
+	return NewAnalyzer(opts...).TopCounts(txt, n)
+}
+
+func TopN(txt string, n int, opts ...Option) []string {
+	return NewAnalyzer(opts...).TopN(txt, n)
+}
+
+func Top10(txt string) []string {
+	return defaultAnalyzer.TopN(txt, 10)
 }
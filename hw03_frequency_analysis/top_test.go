@@ -1,8 +1,11 @@
 package hw03frequencyanalysis
 
 import (
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -135,3 +138,82 @@ func TestTop10(t *testing.T) {
 		}
 	})
 }
+
+func TestTopN(t *testing.T) {
+	t.Run("n limits", func(t *testing.T) {
+		require.Len(t, TopN(text, 0), 0)
+		require.Len(t, TopN(text, -5), 0)
+		require.Equal(t, []string{"а", "он", "и"}, TopN(text, 3))
+		require.Equal(t, []string{"b", "a"}, TopN("a b b", 100))
+	})
+
+	t.Run("without asterisk rules", func(t *testing.T) {
+		expected := []string{"он", "а", "и", "ты", "что", "-", "Кристофер", "если", "не", "то"}
+		require.Equal(t, expected, TopN(text, 10, WithCaseSensitive(), WithPunctuation(""), WithStopWords()))
+	})
+
+	t.Run("stop words", func(t *testing.T) {
+		expected := []string{"ты", "что", "-", "в", "его", "если", "кристофер", "не", "робин", "то"}
+		require.Equal(t, expected, TopN(text, 10, WithStopWords("а", "он", "и")))
+	})
+
+	t.Run("custom tokenizer and normalizer", func(t *testing.T) {
+		tokenizer := func(txt string) []string {
+			return strings.Split(txt, ";")
+		}
+		normalizer := func(word string) string {
+			return strings.TrimSuffix(word, "s")
+		}
+		result := TopN("cats;Cat;dog, dog;cat's", 2, WithTokenizer(tokenizer), WithNormalizer(normalizer))
+		require.Equal(t, []string{"cat", "cat'"}, result)
+	})
+}
+
+func TestTopCounts(t *testing.T) {
+	t.Run("empty string", func(t *testing.T) {
+		counts, total := TopCounts("", 10)
+		require.Len(t, counts, 0)
+		require.Equal(t, 0, total)
+	})
+
+	t.Run("counts and total", func(t *testing.T) {
+		counts, total := TopCounts(text, 5)
+		expected := []WordCount{{"а", 8}, {"он", 8}, {"и", 6}, {"ты", 5}, {"что", 5}}
+		require.Equal(t, expected, counts)
+		require.Equal(t, len(strings.Fields(text))-4, total, "4 dashes are not counted")
+	})
+
+	t.Run("skipped words are not in total", func(t *testing.T) {
+		counts, total := TopCounts("cat, Cat dog - the", 10, WithStopWords("-", "the"))
+		require.Equal(t, []WordCount{{"cat", 2}, {"dog", 1}}, counts)
+		require.Equal(t, 3, total)
+	})
+}
+
+func TestAnalyzerConcurrency(t *testing.T) {
+	const goroutines = 64
+	const iterations = 50
+
+	analyzer := NewAnalyzer(WithCaseSensitive(), WithPunctuation(""), WithStopWords())
+	texts := []string{text, "cat and dog, one dog,two cats and one man", ""}
+	expected := make([][]string, len(texts))
+	expectedTop10 := make([][]string, len(texts))
+	for i, txt := range texts {
+		expected[i] = analyzer.TopN(txt, 7)
+		expectedTop10[i] = Top10(txt)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(goroutines)
+	for g := range goroutines {
+		go func() {
+			defer wg.Done()
+			for i := range iterations {
+				idx := (g + i) % len(texts)
+				assert.Equal(t, expected[idx], analyzer.TopN(texts[idx], 7))
+				assert.Equal(t, expectedTop10[idx], Top10(texts[idx]))
+			}
+		}()
+	}
+	wg.Wait()
+}
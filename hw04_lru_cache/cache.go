@@ -1,25 +1,346 @@
 package hw04lrucache
 
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 type Key string
 
-type Cache interface {
-	Set(key Key, value interface{}) bool
-	Get(key Key) (interface{}, bool)
+var ErrTooHeavy = errors.New("item is heavier than the cache max weight")
+
+// TypedCache is a cache with keys of type K and values of type V.
+type TypedCache[K comparable, V any] interface {
+	Set(key K, value V) bool
+	Get(key K) (V, bool)
 	Clear()
 }
 
-type lruCache struct {
-	Cache // Remove me after realization.
+// Cache is the original untyped cache API.
+type Cache = TypedCache[Key, interface{}]
+
+// LRU is a TypedCache with extra features of the LRU implementation.
+type LRU[K comparable, V any] interface {
+	TypedCache[K, V]
+	// SetWithTTL is Set with its own time to live, ttl <= 0 means the item never expires.
+	SetWithTTL(key K, value V, ttl time.Duration) bool
+	// Delete removes the item and reports if it was in cache.
+	Delete(key K) bool
+	// Len returns the number of items including expired ones that are not removed yet.
+	Len() int
+	// OnEvict sets the function called for every item that leaves the cache.
+	// It is called after the cache is unlocked, so it may use the cache.
+	OnEvict(fn EvictFunc[K, V])
+	Stats() Stats
+	// Save writes a snapshot of live items in recency order with their expiration time
+	// using the codec set by WithCodec.
+	Save(w io.Writer) error
+	// Load adds items from a snapshot written by Save. They are less recent than items already
+	// in cache, and only the most recently used ones are kept if the cache is too small for all.
+	Load(r io.Reader) error
+	// Close stops the janitor, the cache itself stays usable.
+	Close()
+}
+
+// Weigher returns the weight of an item, e.g. its size in bytes. Negative weights are counted as 0.
+type Weigher[K comparable, V any] func(key K, value V) int64
+
+// WeightedLRU is an LRU limited by the total weight of items instead of their number.
+// Set and SetWithTTL silently drop an item heavier than the max weight, TrySet reports it.
+type WeightedLRU[K comparable, V any] interface {
+	LRU[K, V]
+	// TrySet is Set that returns ErrTooHeavy if the item can never fit, an old value of the key is removed then.
+	TrySet(key K, value V) (bool, error)
+	// Weight returns the total weight of items in cache.
+	Weight() int64
+}
+
+type lruCache[K comparable, V any] struct {
+	mu sync.Mutex
+
+	maxWeight int64
+	weight    int64
+	weigher   Weigher[K, V] // nil means every item weighs 1, so maxWeight is the capacity
+	queue     TypedList[cacheItem[K, V]]
+	items     map[K]*TypedListItem[cacheItem[K, V]]
+	clock     *accessClock
+
+	options
+
+	onEvict EvictFunc[K, V]
+	evicted []eviction[K, V] // items removed under the lock, they are passed to onEvict after unlock
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+	size      atomic.Int64
+
+	closeSignal chan struct{}
+	closeOnce   sync.Once
+	janitorDone sync.WaitGroup
+}
+
+// cacheItem is stored in the queue, the key is needed to remove the evicted item from the items map.
+type cacheItem[K comparable, V any] struct {
+	key       K
+	value     V
+	weight    int64
+	expiresAt time.Time // zero if the item never expires
+	used      int64     // accessClock time of the last access, items in the queue are ordered by it
+}
+
+// accessClock orders accesses to items, the shards of a sharded cache share one,
+// so their snapshots can be merged in the recency order of the whole cache.
+type accessClock struct {
+	newest atomic.Int64
+	oldest atomic.Int64
+}
+
+// touch returns the time of an access made now.
+func (c *accessClock) touch() int64 {
+	return c.newest.Add(1)
+}
+
+// backdate returns a time before all previous accesses, for items added as the least recently used.
+func (c *accessClock) backdate() int64 {
+	return c.oldest.Add(-1)
+}
+
+type eviction[K comparable, V any] struct {
+	cacheItem[K, V]
+	reason EvictReason
+}
 
-	capacity int
-	queue    List
-	items    map[Key]*ListItem
+func NewCache(capacity int, opts ...Option) LRU[Key, interface{}] {
+	return NewTypedCache[Key, interface{}](capacity, opts...)
 }
 
-func NewCache(capacity int) Cache {
-	return &lruCache{
-		capacity: capacity,
-		queue:    NewList(),
-		items:    make(map[Key]*ListItem, capacity),
+// NewTypedCache creates an LRU cache that keeps up to capacity items, it is safe for concurrent use.
+func NewTypedCache[K comparable, V any](capacity int, opts ...Option) LRU[K, V] {
+	return newLRUCache[K, V](int64(capacity), nil, max(capacity, 0), opts)
+}
+
+// NewWeightedCache creates an LRU cache that keeps items while their total weight is up to maxWeight,
+// least recently used items are evicted until a new one fits.
+func NewWeightedCache[K comparable, V any](maxWeight int64, weigher Weigher[K, V], opts ...Option) WeightedLRU[K, V] {
+	return newLRUCache(maxWeight, weigher, 0, opts)
+}
+
+func newLRUCache[K comparable, V any](
+	maxWeight int64, weigher Weigher[K, V], sizeHint int, opts []Option,
+) *lruCache[K, V] {
+	c := &lruCache[K, V]{
+		maxWeight:   maxWeight,
+		weigher:     weigher,
+		queue:       NewTypedList[cacheItem[K, V]](),
+		items:       make(map[K]*TypedListItem[cacheItem[K, V]], sizeHint),
+		clock:       &accessClock{},
+		options:     newOptions(opts),
+		closeSignal: make(chan struct{}),
+	}
+	if c.janitorInterval > 0 {
+		c.janitorDone.Add(1)
+		go c.janitor()
+	}
+	return c
+}
+
+func (c *lruCache[K, V]) Set(key K, value V) bool {
+	wasInCache, _ := c.set(key, value, c.ttl)
+	return wasInCache
+}
+
+func (c *lruCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	wasInCache, _ := c.set(key, value, ttl)
+	return wasInCache
+}
+
+func (c *lruCache[K, V]) TrySet(key K, value V) (bool, error) {
+	return c.set(key, value, c.ttl)
+}
+
+func (c *lruCache[K, V]) set(key K, value V, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	item := cacheItem[K, V]{key: key, value: value, weight: 1, used: c.clock.touch()}
+	if c.weigher != nil {
+		item.weight = max(c.weigher(key, value), 0)
+	}
+	if ttl > 0 {
+		item.expiresAt = now.Add(ttl)
+	}
+
+	i, ok := c.items[key]
+	wasAlive := ok && !c.expired(i, now)
+	if item.weight > c.maxWeight {
+		if ok {
+			c.remove(i, EvictCapacity)
+		}
+		return wasAlive, ErrTooHeavy
+	}
+
+	if ok {
+		c.weight += item.weight - i.Value.weight
+		i.Value = item
+		c.queue.MoveToFront(i)
+	} else {
+		c.items[key] = c.queue.PushFront(item)
+		c.weight += item.weight
+		c.size.Add(1)
+	}
+
+	// the new item is at the front and fits alone, so it is never evicted here
+	for c.weight > c.maxWeight {
+		c.remove(c.queue.Back(), EvictCapacity)
+	}
+	return wasAlive, nil
+}
+
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var zero V
+	i, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		return zero, false
+	}
+	if c.expired(i, c.now()) {
+		c.misses.Add(1)
+		c.remove(i, EvictExpired)
+		return zero, false
+	}
+	c.hits.Add(1)
+	i.Value.used = c.clock.touch()
+	c.queue.MoveToFront(i)
+	return i.Value.value, true
+}
+
+func (c *lruCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	i, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	wasAlive := !c.expired(i, c.now())
+	if wasAlive {
+		c.remove(i, EvictDeleted)
+	} else {
+		c.remove(i, EvictExpired)
+	}
+	return wasAlive
+}
+
+func (c *lruCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.onEvict != nil {
+		for i := c.queue.Front(); i != nil; i = i.Next {
+			c.evicted = append(c.evicted, eviction[K, V]{i.Value, EvictCleared})
+		}
+	}
+	c.queue = NewTypedList[cacheItem[K, V]]()
+	c.items = make(map[K]*TypedListItem[cacheItem[K, V]], len(c.items))
+	c.weight = 0
+	c.size.Store(0)
+}
+
+func (c *lruCache[K, V]) OnEvict(fn EvictFunc[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvict = fn
+}
+
+func (c *lruCache[K, V]) Weight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.weight
+}
+
+func (c *lruCache[K, V]) Stats() Stats {
+	return newStats(c.hits.Load(), c.misses.Load(), c.evictions.Load(), c.size.Load())
+}
+
+func (c *lruCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.queue.Len()
+}
+
+func (c *lruCache[K, V]) Close() {
+	c.closeOnce.Do(func() { close(c.closeSignal) })
+	c.janitorDone.Wait()
+}
+
+func (c *lruCache[K, V]) expired(i *TypedListItem[cacheItem[K, V]], now time.Time) bool {
+	expiresAt := i.Value.expiresAt
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+func (c *lruCache[K, V]) remove(i *TypedListItem[cacheItem[K, V]], reason EvictReason) {
+	item := i.Value
+	c.queue.Remove(i)
+	delete(c.items, item.key)
+	c.weight -= item.weight
+	c.size.Add(-1)
+
+	if reason == EvictCapacity || reason == EvictExpired {
+		c.evictions.Add(1)
+	}
+	if c.onEvict != nil {
+		c.evicted = append(c.evicted, eviction[K, V]{item, reason})
+	}
+}
+
+// unlock releases the lock and then passes items removed under it to onEvict.
+func (c *lruCache[K, V]) unlock() {
+	onEvict, evicted := c.onEvict, c.evicted
+	c.evicted = nil
+	c.mu.Unlock()
+
+	for _, e := range evicted {
+		onEvict(e.key, e.value, e.reason)
+	}
+}
+
+func (c *lruCache[K, V]) janitor() {
+	defer c.janitorDone.Done()
+
+	ticker := time.NewTicker(c.janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closeSignal:
+			return
+		case <-ticker.C:
+			c.removeExpired()
+		}
+	}
+}
+
+func (c *lruCache[K, V]) removeExpired() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now()
+	for i := c.queue.Front(); i != nil; {
+		next := i.Next
+		if c.expired(i, now) {
+			c.remove(i, EvictExpired)
+		}
+		i = next
 	}
 }
@@ -50,13 +50,61 @@ func TestCache(t *testing.T) {
 	})
 
 	t.Run("purge logic", func(t *testing.T) {
-		// Write me
+		c := NewCache(3)
+
+		c.Set("aaa", 1)
+		c.Set("bbb", 2)
+		c.Set("ccc", 3)
+		c.Set("ddd", 4) // "aaa" is pushed out by size
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+
+		c.Get("bbb")     // [bbb, ddd, ccc]
+		c.Set("ccc", 30) // [ccc, bbb, ddd]
+		c.Set("eee", 5)  // "ddd" is the least recently used
+
+		_, ok = c.Get("ddd")
+		require.False(t, ok)
+		for key, expected := range map[Key]int{"bbb": 2, "ccc": 30, "eee": 5} {
+			val, ok := c.Get(key)
+			require.True(t, ok)
+			require.Equal(t, expected, val)
+		}
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		c := NewCache(3)
+		c.Set("aaa", 1)
+		c.Set("bbb", 2)
+
+		c.Clear()
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+
+		require.False(t, c.Set("aaa", 3))
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		c := NewTypedCache[int, string](2)
+		c.Set(1, "one")
+		c.Set(2, "two")
+		c.Set(3, "three")
+
+		val, ok := c.Get(1)
+		require.False(t, ok)
+		require.Equal(t, "", val)
+
+		val, ok = c.Get(3)
+		require.True(t, ok)
+		require.Equal(t, "three", val)
 	})
 }
 
 func TestCacheMultithreading(t *testing.T) {
-	t.Skip() // Remove me if task with asterisk completed.
-
 	c := NewCache(10)
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
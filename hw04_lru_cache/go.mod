@@ -1,11 +1,15 @@
 module github.com/fixme_my_friend/hw04_lru_cache
 
-go 1.22
+go 1.23
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/stretchr/testify v1.8.0
+	go.uber.org/goleak v1.3.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
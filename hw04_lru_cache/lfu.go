@@ -0,0 +1,102 @@
+package hw04lrucache
+
+import (
+	"sync"
+)
+
+// lfuCache evicts the least frequently used item, among items with equal frequency the least recently used one.
+// All operations are O(1): items of every frequency are kept in their own list.
+type lfuCache[K comparable, V any] struct {
+	mu sync.Mutex
+
+	capacity int
+	items    map[K]*TypedListItem[lfuItem[K, V]]
+	freqs    map[int]TypedList[lfuItem[K, V]] // frequency to items used that many times, most recent at front
+	minFreq  int
+}
+
+type lfuItem[K comparable, V any] struct {
+	key   K
+	value V
+	freq  int
+}
+
+// NewLFUCache creates an LFU cache that keeps up to capacity items, it is safe for concurrent use.
+func NewLFUCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	return &lfuCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*TypedListItem[lfuItem[K, V]], max(capacity, 0)),
+		freqs:    map[int]TypedList[lfuItem[K, V]]{},
+	}
+}
+
+func (c *lfuCache[K, V]) Set(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, ok := c.items[key]; ok {
+		i.Value.value = value
+		c.touch(i)
+		return true
+	}
+	if c.capacity <= 0 {
+		return false
+	}
+
+	if len(c.items) >= c.capacity {
+		victims := c.freqs[c.minFreq]
+		last := victims.Back()
+		victims.Remove(last)
+		delete(c.items, last.Value.key)
+	}
+	c.items[key] = c.list(1).PushFront(lfuItem[K, V]{key: key, value: value, freq: 1})
+	c.minFreq = 1
+	return false
+}
+
+func (c *lfuCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.touch(i)
+	return i.Value.value, true
+}
+
+func (c *lfuCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*TypedListItem[lfuItem[K, V]], max(c.capacity, 0))
+	c.freqs = map[int]TypedList[lfuItem[K, V]]{}
+	c.minFreq = 0
+}
+
+// touch moves the item to the list of the next frequency.
+func (c *lfuCache[K, V]) touch(i *TypedListItem[lfuItem[K, V]]) {
+	item := i.Value
+	from := c.freqs[item.freq]
+	from.Remove(i)
+	if from.Len() == 0 {
+		delete(c.freqs, item.freq)
+		if c.minFreq == item.freq {
+			c.minFreq++
+		}
+	}
+
+	item.freq++
+	c.items[item.key] = c.list(item.freq).PushFront(item)
+}
+
+func (c *lfuCache[K, V]) list(freq int) TypedList[lfuItem[K, V]] {
+	l, ok := c.freqs[freq]
+	if !ok {
+		l = NewTypedList[lfuItem[K, V]]()
+		c.freqs[freq] = l
+	}
+	return l
+}
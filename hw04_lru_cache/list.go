@@ -1,26 +1,182 @@
 package hw04lrucache
 
-type List interface {
+import (
+	"iter"
+)
+
+// TypedList is a doubly linked list of values of type T.
+// Methods taking an item do nothing if the item belongs to another list or was removed.
+type TypedList[T any] interface {
 	Len() int
-	Front() *ListItem
-	Back() *ListItem
-	PushFront(v interface{}) *ListItem
-	PushBack(v interface{}) *ListItem
-	Remove(i *ListItem)
-	MoveToFront(i *ListItem)
+	Front() *TypedListItem[T]
+	Back() *TypedListItem[T]
+	PushFront(v T) *TypedListItem[T]
+	PushBack(v T) *TypedListItem[T]
+	// InsertBefore inserts v right before mark and returns its item, or nil if mark is not in the list.
+	InsertBefore(v T, mark *TypedListItem[T]) *TypedListItem[T]
+	// InsertAfter inserts v right after mark and returns its item, or nil if mark is not in the list.
+	InsertAfter(v T, mark *TypedListItem[T]) *TypedListItem[T]
+	Remove(i *TypedListItem[T])
+	MoveToFront(i *TypedListItem[T])
+	MoveToBack(i *TypedListItem[T])
+	MoveBefore(i, mark *TypedListItem[T])
+	MoveAfter(i, mark *TypedListItem[T])
+	// All iterates over values from front to back.
+	All() iter.Seq[T]
+	// Backward iterates over values from back to front.
+	Backward() iter.Seq[T]
 }
 
-type ListItem struct {
-	Value interface{}
-	Next  *ListItem
-	Prev  *ListItem
+type TypedListItem[T any] struct {
+	Value T
+	Next  *TypedListItem[T]
+	Prev  *TypedListItem[T]
+
+	list *list[T] // nil if the item is not in a list
 }
 
-type list struct {
-	List // Remove me after realization.
-	// Place your code here.
+// List is the original untyped list API.
+type (
+	List     = TypedList[interface{}]
+	ListItem = TypedListItem[interface{}]
+)
+
+type list[T any] struct {
+	len   int
+	front *TypedListItem[T]
+	back  *TypedListItem[T]
 }
 
 func NewList() List {
-	return new(list)
+	return NewTypedList[interface{}]()
+}
+
+func NewTypedList[T any]() TypedList[T] {
+	return new(list[T])
+}
+
+func (l *list[T]) Len() int {
+	return l.len
+}
+
+func (l *list[T]) Front() *TypedListItem[T] {
+	return l.front
+}
+
+func (l *list[T]) Back() *TypedListItem[T] {
+	return l.back
+}
+
+func (l *list[T]) PushFront(v T) *TypedListItem[T] {
+	return l.insertAfter(&TypedListItem[T]{Value: v}, nil)
+}
+
+func (l *list[T]) PushBack(v T) *TypedListItem[T] {
+	return l.insertAfter(&TypedListItem[T]{Value: v}, l.back)
+}
+
+func (l *list[T]) InsertBefore(v T, mark *TypedListItem[T]) *TypedListItem[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insertAfter(&TypedListItem[T]{Value: v}, mark.Prev)
+}
+
+func (l *list[T]) InsertAfter(v T, mark *TypedListItem[T]) *TypedListItem[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insertAfter(&TypedListItem[T]{Value: v}, mark)
+}
+
+func (l *list[T]) Remove(i *TypedListItem[T]) {
+	if i.list != l {
+		return
+	}
+	l.unlink(i)
+}
+
+func (l *list[T]) MoveToFront(i *TypedListItem[T]) {
+	if i.list != l || l.front == i {
+		return
+	}
+	l.unlink(i)
+	l.insertAfter(i, nil)
+}
+
+func (l *list[T]) MoveToBack(i *TypedListItem[T]) {
+	if i.list != l || l.back == i {
+		return
+	}
+	l.unlink(i)
+	l.insertAfter(i, l.back)
+}
+
+func (l *list[T]) MoveBefore(i, mark *TypedListItem[T]) {
+	if i.list != l || mark.list != l || i == mark {
+		return
+	}
+	l.unlink(i)
+	l.insertAfter(i, mark.Prev)
+}
+
+func (l *list[T]) MoveAfter(i, mark *TypedListItem[T]) {
+	if i.list != l || mark.list != l || i == mark {
+		return
+	}
+	l.unlink(i)
+	l.insertAfter(i, mark)
+}
+
+func (l *list[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.front; i != nil; i = i.Next {
+			if !yield(i.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (l *list[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := l.back; i != nil; i = i.Prev {
+			if !yield(i.Value) {
+				return
+			}
+		}
+	}
+}
+
+// insertAfter links a detached item after at, or to the front if at is nil.
+func (l *list[T]) insertAfter(i, at *TypedListItem[T]) *TypedListItem[T] {
+	i.Prev = at
+	if at == nil {
+		i.Next, l.front = l.front, i
+	} else {
+		i.Next, at.Next = at.Next, i
+	}
+	if i.Next == nil {
+		l.back = i
+	} else {
+		i.Next.Prev = i
+	}
+	i.list = l
+	l.len++
+	return i
+}
+
+func (l *list[T]) unlink(i *TypedListItem[T]) {
+	if i.Prev == nil {
+		l.front = i.Next
+	} else {
+		i.Prev.Next = i.Next
+	}
+	if i.Next == nil {
+		l.back = i.Prev
+	} else {
+		i.Next.Prev = i.Prev
+	}
+	i.Next, i.Prev, i.list = nil, nil, nil
+	l.len--
 }
@@ -1,6 +1,9 @@
 package hw04lrucache
 
 import (
+	containerlist "container/list"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -48,4 +51,150 @@ func TestList(t *testing.T) {
 		}
 		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, elems)
 	})
+
+	t.Run("remove and move edges", func(t *testing.T) {
+		l := NewList()
+
+		single := l.PushBack(1) // [1]
+		l.Remove(single)        // []
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+
+		first := l.PushBack(1) // [1]
+		l.PushBack(2)          // [1, 2]
+		last := l.PushBack(3)  // [1, 2, 3]
+		l.MoveToFront(last)    // [3, 1, 2]
+		require.Equal(t, 3, l.Front().Value)
+		require.Equal(t, 2, l.Back().Value)
+		require.Nil(t, l.Front().Prev)
+		require.Nil(t, l.Back().Next)
+
+		l.Remove(l.Front()) // [1, 2]
+		l.Remove(l.Back())  // [1]
+		require.Equal(t, 1, l.Len())
+		require.Equal(t, first, l.Front())
+		require.Equal(t, first, l.Back())
+	})
+	t.Run("insert and move", func(t *testing.T) {
+		l := NewTypedList[int]()
+
+		two := l.PushBack(2)          // [2]
+		one := l.InsertBefore(1, two) // [1, 2]
+		four := l.InsertAfter(4, two) // [1, 2, 4]
+		l.InsertAfter(3, two)         // [1, 2, 3, 4]
+		require.Equal(t, []int{1, 2, 3, 4}, slices.Collect(l.All()))
+
+		l.MoveToBack(one)       // [2, 3, 4, 1]
+		l.MoveBefore(four, two) // [4, 2, 3, 1]
+		l.MoveAfter(two, one)   // [4, 3, 1, 2]
+		l.MoveAfter(two, one)   // already there
+		l.MoveBefore(one, one)  // [4, 3, 1, 2]
+		require.Equal(t, []int{4, 3, 1, 2}, slices.Collect(l.All()))
+		require.Equal(t, []int{2, 1, 3, 4}, slices.Collect(l.Backward()))
+		require.Equal(t, 4, l.Len())
+		require.Nil(t, l.Front().Prev)
+		require.Nil(t, l.Back().Next)
+	})
+
+	t.Run("iteration stops on break", func(t *testing.T) {
+		l := NewTypedList[int]()
+		for v := range 10 {
+			l.PushBack(v)
+		}
+
+		var seen []int
+		for v := range l.Backward() {
+			if v < 7 {
+				break
+			}
+			seen = append(seen, v)
+		}
+		require.Equal(t, []int{9, 8, 7}, seen)
+	})
+
+	t.Run("items of another list", func(t *testing.T) {
+		l, other := NewTypedList[int](), NewTypedList[int]()
+		mine := l.PushBack(1)
+		l.PushBack(2)
+		foreign := other.PushBack(10)
+
+		l.Remove(foreign)
+		l.MoveToFront(foreign)
+		l.MoveToBack(foreign)
+		l.MoveBefore(foreign, mine)
+		l.MoveAfter(mine, foreign)
+		require.Nil(t, l.InsertBefore(3, foreign))
+		require.Nil(t, l.InsertAfter(3, foreign))
+
+		require.Equal(t, []int{1, 2}, slices.Collect(l.All()))
+		require.Equal(t, []int{10}, slices.Collect(other.All()))
+
+		l.Remove(mine)
+		l.Remove(mine) // removed item is not in l anymore
+		require.Equal(t, 1, l.Len())
+		require.Nil(t, l.InsertAfter(3, mine))
+		require.Equal(t, []int{2}, slices.Collect(l.All()))
+	})
+}
+
+// TestListAsContainerList runs the same random operations on List and container/list.
+func TestListAsContainerList(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	l, expected := NewTypedList[int](), containerlist.New()
+	var items []*TypedListItem[int]
+	var elems []*containerlist.Element
+
+	for op := range 10_000 {
+		i, j := -1, -1
+		if len(items) > 0 {
+			i, j = rnd.Intn(len(items)), rnd.Intn(len(items))
+		}
+		switch k := rnd.Intn(9); {
+		case k == 0 || i < 0:
+			items, elems = append(items, l.PushFront(op)), append(elems, expected.PushFront(op))
+		case k == 1:
+			items, elems = append(items, l.PushBack(op)), append(elems, expected.PushBack(op))
+		case k == 2:
+			items, elems = append(items, l.InsertBefore(op, items[i])), append(elems, expected.InsertBefore(op, elems[i]))
+		case k == 3:
+			items, elems = append(items, l.InsertAfter(op, items[i])), append(elems, expected.InsertAfter(op, elems[i]))
+		case k == 4:
+			l.Remove(items[i])
+			expected.Remove(elems[i])
+			items, elems = slices.Delete(items, i, i+1), slices.Delete(elems, i, i+1)
+		case k == 5:
+			l.MoveToFront(items[i])
+			expected.MoveToFront(elems[i])
+		case k == 6:
+			l.MoveToBack(items[i])
+			expected.MoveToBack(elems[i])
+		case k == 7:
+			l.MoveBefore(items[i], items[j])
+			expected.MoveBefore(elems[i], elems[j])
+		default:
+			l.MoveAfter(items[i], items[j])
+			expected.MoveAfter(elems[i], elems[j])
+		}
+
+		require.Equal(t, expected.Len(), l.Len())
+		if op%100 == 0 {
+			requireSameList(t, expected, l)
+		}
+	}
+	requireSameList(t, expected, l)
+}
+
+func requireSameList(t *testing.T, expected *containerlist.List, l TypedList[int]) {
+	t.Helper()
+
+	var forward, backward []int
+	for e := expected.Front(); e != nil; e = e.Next() {
+		forward = append(forward, e.Value.(int))
+	}
+	for e := expected.Back(); e != nil; e = e.Prev() {
+		backward = append(backward, e.Value.(int))
+	}
+	require.Equal(t, forward, slices.Collect(l.All()))
+	require.Equal(t, backward, slices.Collect(l.Backward()))
 }
@@ -0,0 +1,165 @@
+package hw04lrucache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader loads the value of a key missing in cache, e.g. from a database.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// LoadingCache is a TypedCache that loads missing values by itself.
+type LoadingCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	// GetOrLoad returns the cached value or calls loader and caches its result.
+	// Concurrent calls for the same missing key share one loader call. The loader gets a context
+	// that is canceled only when every caller waiting for it has given up. A loader error is returned
+	// and not cached, unless WithNegativeTTL is set. The loaded value is not cached if the key
+	// is set or the cache is cleared before the loader returns. A Set or Clear made after the loader
+	// has returned but before GetOrLoad does may still be overwritten by the loaded value.
+	GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error)
+}
+
+type loadingCache[K comparable, V any] struct {
+	TypedCache[K, V]
+
+	mu       sync.Mutex
+	calls    map[K]*loadCall[V]
+	failures map[K]loadFailure
+
+	options
+}
+
+// loadCall is a loader call shared by all callers waiting for the same key.
+type loadCall[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+	stale   bool // Set or Clear has been called during the load, so its value must not be cached
+}
+
+type loadFailure struct {
+	err       error
+	expiresAt time.Time
+}
+
+// NewLoadingCache adds GetOrLoad to cache, WithNegativeTTL and WithClock options are used.
+func NewLoadingCache[K comparable, V any](cache TypedCache[K, V], opts ...Option) LoadingCache[K, V] {
+	return &loadingCache[K, V]{
+		TypedCache: cache,
+		calls:      map[K]*loadCall[V]{},
+		failures:   map[K]loadFailure{},
+		options:    newOptions(opts),
+	}
+}
+
+func (c *loadingCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	if f, ok := c.failures[key]; ok {
+		if c.now().Before(f.expiresAt) {
+			c.mu.Unlock()
+			var zero V
+			return zero, f.err
+		}
+		delete(c.failures, key)
+	}
+	call, ok := c.calls[key]
+	if !ok {
+		call = c.startLoad(ctx, key, loader)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		c.leave(key, call)
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// leave removes a waiter that has given up, the load is canceled when nobody waits for it.
+func (c *loadingCache[K, V]) leave(key K, call *loadCall[V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	// the call may be finished already and the key taken by a newer one, which must stay
+	if c.calls[key] == call {
+		// nobody needs the result, the next caller starts a fresh load
+		delete(c.calls, key)
+	}
+	call.cancel()
+}
+
+// startLoad runs loader in its own goroutine, so the caller that started it can give up waiting
+// without canceling the load for others. Must be called under c.mu.
+func (c *loadingCache[K, V]) startLoad(ctx context.Context, key K, loader Loader[K, V]) *loadCall[V] {
+	loadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &loadCall[V]{done: make(chan struct{}), cancel: cancel}
+	c.calls[key] = call
+
+	go func() {
+		defer close(call.done)
+		defer cancel()
+
+		value, err := loader(loadCtx, key)
+
+		// a call abandoned by all waiters is already removed from c.calls
+		c.mu.Lock()
+		stale := call.stale || c.calls[key] != call
+		if err != nil && !stale && c.negativeTTL > 0 && loadCtx.Err() == nil {
+			c.failures[key] = loadFailure{err: err, expiresAt: c.now().Add(c.negativeTTL)}
+		}
+		c.mu.Unlock()
+
+		// the inner cache may call OnEvict callbacks that use this cache, so c.mu must not be held.
+		// A Set or Clear made after the check above is not seen and may be overwritten.
+		if err == nil && !stale {
+			c.TypedCache.Set(key, value)
+		}
+
+		c.mu.Lock()
+		if c.calls[key] == call {
+			delete(c.calls, key)
+		}
+		call.value, call.err = value, err
+		c.mu.Unlock()
+	}()
+
+	return call
+}
+
+func (c *loadingCache[K, V]) Set(key K, value V) bool {
+	c.mu.Lock()
+	delete(c.failures, key)
+	if call, ok := c.calls[key]; ok {
+		call.stale = true
+	}
+	c.mu.Unlock()
+
+	return c.TypedCache.Set(key, value)
+}
+
+func (c *loadingCache[K, V]) Clear() {
+	c.mu.Lock()
+	clear(c.failures)
+	for _, call := range c.calls {
+		call.stale = true
+	}
+	c.mu.Unlock()
+
+	c.TypedCache.Clear()
+}
@@ -0,0 +1,242 @@
+package hw04lrucache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestLoadingCache(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("concurrent misses share one load", func(t *testing.T) {
+		c := NewLoadingCache[string, int](NewTypedCache[string, int](10))
+		var calls atomic.Int32
+		release := make(chan struct{})
+		loader := func(_ context.Context, key string) (int, error) {
+			calls.Add(1)
+			<-release
+			return len(key), nil
+		}
+
+		const callers = 50
+		results := make(chan int, callers)
+		errs := make(chan error, callers)
+		wg := sync.WaitGroup{}
+		wg.Add(callers)
+		for range callers {
+			go func() {
+				defer wg.Done()
+				value, err := c.GetOrLoad(context.Background(), "aaa", loader)
+				results <- value
+				errs <- err
+			}()
+		}
+		require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(results)
+		close(errs)
+
+		for value := range results {
+			require.Equal(t, 3, value)
+		}
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(1), calls.Load())
+
+		value, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, 3, value)
+		require.Equal(t, int32(1), calls.Load(), "loaded value is cached")
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		c := NewLoadingCache[string, int](NewTypedCache[string, int](10))
+		errLoad := errors.New("db is down")
+		calls := 0
+		loader := func(_ context.Context, _ string) (int, error) {
+			calls++
+			return 0, errLoad
+		}
+
+		for range 3 {
+			_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+			require.ErrorIs(t, err, errLoad)
+		}
+		require.Equal(t, 3, calls)
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewLoadingCache[string, int](NewTypedCache[string, int](10),
+			WithNegativeTTL(time.Minute), WithClock(clock.Now))
+		errLoad := errors.New("not found")
+		calls := 0
+		loader := func(_ context.Context, _ string) (int, error) {
+			calls++
+			return 0, errLoad
+		}
+
+		for range 3 {
+			_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+			require.ErrorIs(t, err, errLoad)
+		}
+		require.Equal(t, 1, calls)
+
+		clock.Advance(time.Minute)
+		_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.ErrorIs(t, err, errLoad)
+		require.Equal(t, 2, calls)
+
+		c.Set("aaa", 1) // Set forgets the error
+		value, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, 1, value)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		c := NewLoadingCache[string, int](NewTypedCache[string, int](10), WithNegativeTTL(time.Minute))
+		started := make(chan struct{})
+		loadCanceled := make(chan struct{})
+		loader := func(ctx context.Context, _ string) (int, error) {
+			close(started)
+			<-ctx.Done()
+			close(loadCanceled)
+			return 0, ctx.Err()
+		}
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel2()
+		errs := make(chan error, 2)
+		go func() {
+			_, err := c.GetOrLoad(ctx1, "aaa", loader)
+			errs <- err
+		}()
+		<-started
+		go func() {
+			_, err := c.GetOrLoad(ctx2, "aaa", loader)
+			errs <- err
+		}()
+		require.Eventually(t, func() bool { return loadWaiters(c, "aaa") == 2 }, time.Second, time.Millisecond)
+
+		cancel1()
+		require.ErrorIs(t, <-errs, context.Canceled)
+		select {
+		case <-loadCanceled:
+			t.Fatal("load is canceled while another caller waits for it")
+		default:
+		}
+
+		require.ErrorIs(t, <-errs, context.DeadlineExceeded)
+		<-loadCanceled
+
+		// the canceled load is not remembered as a failure
+		value, err := c.GetOrLoad(context.Background(), "aaa", func(_ context.Context, _ string) (int, error) {
+			return 7, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 7, value)
+	})
+
+	t.Run("waiter of a finished load leaves during a newer load", func(t *testing.T) {
+		c := NewLoadingCache[string, int](NewTypedCache[string, int](10))
+		lc := c.(*loadingCache[string, int])
+
+		// the first load has finished with an error, its last waiter has not seen it yet
+		finished := &loadCall[int]{done: make(chan struct{}), waiters: 1, cancel: func() {}}
+		close(finished.done)
+
+		started, release := make(chan struct{}), make(chan struct{})
+		loaded := make(chan int)
+		go func() {
+			value, _ := c.GetOrLoad(context.Background(), "aaa", func(_ context.Context, _ string) (int, error) {
+				close(started)
+				<-release
+				return 2, nil
+			})
+			loaded <- value
+		}()
+		<-started
+
+		lc.leave("aaa", finished)
+		require.Equal(t, 1, loadWaiters(c, "aaa"), "newer load is forgotten")
+
+		close(release)
+		require.Equal(t, 2, <-loaded)
+		value, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 2, value)
+	})
+
+	t.Run("set during load wins", func(t *testing.T) {
+		c := NewLoadingCache[string, int](NewTypedCache[string, int](10))
+		started, release := make(chan struct{}), make(chan struct{})
+		loaded := make(chan int)
+		go func() {
+			value, _ := c.GetOrLoad(context.Background(), "aaa", func(_ context.Context, _ string) (int, error) {
+				close(started)
+				<-release
+				return 1, nil
+			})
+			loaded <- value
+		}()
+		<-started
+
+		c.Set("aaa", 2)
+		close(release)
+		require.Equal(t, 1, <-loaded, "waiters get the loaded value")
+
+		value, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 2, value, "loaded value overwrote a newer one")
+	})
+
+	t.Run("evict callback uses the loading cache", func(t *testing.T) {
+		inner := NewTypedCache[string, int](1)
+		c := NewLoadingCache[string, int](inner)
+		inner.OnEvict(func(key string, _ int, reason EvictReason) {
+			if reason == EvictCapacity && !strings.HasPrefix(key, "evicted-") {
+				c.Set("evicted-"+key, 0)
+			}
+		})
+		c.Set("a", 1)
+
+		loaded, errs := make(chan int, 1), make(chan error, 1)
+		go func() {
+			value, err := c.GetOrLoad(context.Background(), "b", func(_ context.Context, _ string) (int, error) {
+				return 2, nil
+			})
+			loaded <- value
+			errs <- err
+		}()
+		select {
+		case err := <-errs:
+			require.NoError(t, err)
+			require.Equal(t, 2, <-loaded)
+		case <-time.After(time.Second):
+			t.Fatal("GetOrLoad is deadlocked by the evict callback")
+		}
+	})
+}
+
+func loadWaiters[K comparable, V any](c LoadingCache[K, V], key K) int {
+	lc := c.(*loadingCache[K, V])
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if call, ok := lc.calls[key]; ok {
+		return call.waiters
+	}
+	return 0
+}
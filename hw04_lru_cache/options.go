@@ -0,0 +1,60 @@
+package hw04lrucache
+
+import (
+	"time"
+)
+
+type options struct {
+	ttl             time.Duration
+	now             func() time.Time
+	janitorInterval time.Duration
+	negativeTTL     time.Duration
+	codec           Codec
+}
+
+type Option func(o *options)
+
+func newOptions(opts []Option) options {
+	o := options{now: time.Now, codec: GobCodec}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithDefaultTTL sets the time to live of items added by Set, ttl <= 0 means items never expire.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithClock replaces time.Now used to check expiration.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithJanitor starts a goroutine that removes expired items every interval until the cache is closed.
+// Without it expired items are removed only when they are accessed or pushed out by size.
+func WithJanitor(interval time.Duration) Option {
+	return func(o *options) {
+		o.janitorInterval = interval
+	}
+}
+
+// WithNegativeTTL makes a loading cache remember loader errors for ttl,
+// so a failing key is not loaded again until then. By default errors are not remembered.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithCodec sets the codec used by Save and Load, GobCodec is used by default.
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
@@ -0,0 +1,200 @@
+package hw04lrucache
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate go run testdata/traces/generate.go
+
+var policies = []struct {
+	name string
+	new  func(capacity int) TypedCache[string, struct{}]
+}{
+	{"lru", func(capacity int) TypedCache[string, struct{}] { return NewTypedCache[string, struct{}](capacity) }},
+	{"lfu", NewLFUCache[string, struct{}]},
+	{"2q", NewTwoQueueCache[string, struct{}]},
+}
+
+// loadTrace reads a synthetic key trace, one key per line, made by testdata/traces/generate.go.
+func loadTrace(tb testing.TB, path string) []string {
+	tb.Helper()
+	f, err := os.Open(path)
+	require.NoError(tb, err)
+	defer f.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		keys = append(keys, scanner.Text())
+	}
+	require.NoError(tb, scanner.Err())
+	return keys
+}
+
+// replay looks every key up and sets it on a miss, like a read-through cache does, and returns the hit ratio.
+func replay(c TypedCache[string, struct{}], keys []string) float64 {
+	hits := 0
+	for _, key := range keys {
+		if _, ok := c.Get(key); ok {
+			hits++
+		} else {
+			c.Set(key, struct{}{})
+		}
+	}
+	return float64(hits) / float64(len(keys))
+}
+
+func TestPolicies(t *testing.T) {
+	for _, policy := range policies {
+		t.Run(policy.name, func(t *testing.T) {
+			c := policy.new(3)
+
+			require.False(t, c.Set("aaa", struct{}{}))
+			require.True(t, c.Set("aaa", struct{}{}))
+			_, ok := c.Get("aaa")
+			require.True(t, ok)
+			_, ok = c.Get("bbb")
+			require.False(t, ok)
+
+			for i := range 10 {
+				c.Set(strconv.Itoa(i), struct{}{})
+			}
+			hits := 0
+			for i := range 10 {
+				if _, ok := c.Get(strconv.Itoa(i)); ok {
+					hits++
+				}
+			}
+			require.LessOrEqual(t, hits, 3, "no more than capacity items are kept")
+
+			c.Clear()
+			_, ok = c.Get("aaa")
+			require.False(t, ok)
+
+			empty := policy.new(0)
+			require.False(t, empty.Set("aaa", struct{}{}))
+			_, ok = empty.Get("aaa")
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestLFUEviction(t *testing.T) {
+	c := NewLFUCache[string, int](3)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Get("c")
+	c.Set("b", 20) // frequencies: a=3, b=3, c=2
+
+	c.Set("d", 4) // c is the least frequently used
+	_, ok := c.Get("c")
+	require.False(t, ok)
+
+	c.Set("e", 5) // d and e have frequency 1, d is older
+	_, ok = c.Get("d")
+	require.False(t, ok)
+	for key, expected := range map[string]int{"a": 1, "b": 20, "e": 5} {
+		val, ok := c.Get(key)
+		require.True(t, ok)
+		require.Equal(t, expected, val)
+	}
+}
+
+func TestTwoQueueScanResistance(t *testing.T) {
+	c := NewTwoQueueCache[int, int](8) // 2 items in recentIn, 4 ghost keys
+
+	// the working set is evicted from recentIn once and comes back through the ghost queue
+	for key := range 4 {
+		c.Set(key, key)
+	}
+	for key := 10; key < 18; key++ {
+		c.Set(key, 0)
+	}
+	for key := range 4 {
+		_, ok := c.Get(key)
+		require.False(t, ok, "key %d is not evicted", key)
+		c.Set(key, key)
+	}
+	for key := 100; key < 200; key++ {
+		c.Set(key, 0) // a long scan
+	}
+	for key := range 4 {
+		val, ok := c.Get(key)
+		require.True(t, ok, "key %d is flushed by the scan", key)
+		require.Equal(t, key, val)
+	}
+
+	t.Run("correlated hits", func(t *testing.T) {
+		c := NewTwoQueueCache[int, int](8)
+		c.Set(0, 0)
+		for range 10 {
+			_, ok := c.Get(0) // hits right after the first use don't promote the key
+			require.True(t, ok)
+		}
+		for key := 100; key < 200; key++ {
+			c.Set(key, 0)
+		}
+		_, ok := c.Get(0)
+		require.False(t, ok)
+	})
+
+	t.Run("ghost keys", func(t *testing.T) {
+		c := NewTwoQueueCache[int, int](8)
+		for key := range 10 {
+			c.Set(key, key) // 0 and 1 are evicted and remembered
+		}
+		_, ok := c.Get(0)
+		require.False(t, ok)
+
+		c.Set(0, 100) // back from the ghost queue straight to frequent
+		for key := 100; key < 200; key++ {
+			c.Set(key, 0)
+		}
+		val, ok := c.Get(0)
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+	})
+}
+
+func TestTraceHitRatio(t *testing.T) {
+	keys := loadTrace(t, "testdata/traces/scan.trace")
+
+	ratios := map[string]float64{}
+	for _, policy := range policies {
+		ratios[policy.name] = replay(policy.new(300), keys)
+	}
+	require.Greater(t, ratios["2q"], ratios["lru"], "2Q must survive scans better than LRU")
+	require.Greater(t, ratios["lfu"], ratios["lru"], "LFU must survive scans better than LRU")
+}
+
+func BenchmarkTraceReplay(b *testing.B) {
+	traces, err := filepath.Glob("testdata/traces/*.trace")
+	require.NoError(b, err)
+
+	for _, path := range traces {
+		keys := loadTrace(b, path)
+		trace := strings.TrimSuffix(filepath.Base(path), ".trace")
+		for _, capacity := range []int{100, 300, 1000} {
+			for _, policy := range policies {
+				b.Run(trace+"/capacity="+strconv.Itoa(capacity)+"/"+policy.name, func(b *testing.B) {
+					var ratio float64
+					for range b.N {
+						ratio = replay(policy.new(capacity), keys)
+					}
+					b.ReportMetric(ratio, "hit-ratio")
+				})
+			}
+		}
+	}
+}
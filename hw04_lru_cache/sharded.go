@@ -0,0 +1,141 @@
+package hw04lrucache
+
+import (
+	"cmp"
+	"hash/maphash"
+	"io"
+	"slices"
+	"time"
+)
+
+// shardedCache spreads keys over independent LRU shards, so goroutines working with different shards
+// don't wait for each other. Items are evicted by recency within their shard, not the whole cache,
+// but the shards share an access clock, so snapshots keep the recency order of the whole cache.
+type shardedCache[K comparable, V any] struct {
+	shards []*lruCache[K, V]
+	hash   func(key K) uint64
+}
+
+// NewShardedCache creates a sharded cache with Key keys, see NewTypedShardedCache.
+func NewShardedCache(capacity, shards int, opts ...Option) LRU[Key, interface{}] {
+	seed := maphash.MakeSeed()
+	hash := func(key Key) uint64 {
+		return maphash.String(seed, string(key))
+	}
+	return NewTypedShardedCache[Key, interface{}](capacity, shards, hash, opts...)
+}
+
+// NewTypedShardedCache creates a cache of shards LRU caches with capacity split between them,
+// hash picks the shard of a key. Options are applied to every shard.
+// Every shard gets at least one item, so there are at most capacity shards.
+func NewTypedShardedCache[K comparable, V any](
+	capacity, shards int, hash func(key K) uint64, opts ...Option,
+) LRU[K, V] {
+	capacity = max(capacity, 0)
+	shards = max(min(shards, capacity), 1)
+
+	c := &shardedCache[K, V]{
+		shards: make([]*lruCache[K, V], shards),
+		hash:   hash,
+	}
+	clock := &accessClock{}
+	for i := range c.shards {
+		// the first capacity%shards shards take one item of the remainder each
+		shardCapacity := capacity / shards
+		if i < capacity%shards {
+			shardCapacity++
+		}
+		c.shards[i] = newLRUCache[K, V](int64(shardCapacity), nil, shardCapacity, opts)
+		c.shards[i].clock = clock
+	}
+	return c
+}
+
+func (c *shardedCache[K, V]) shard(key K) *lruCache[K, V] {
+	return c.shards[c.hash(key)%uint64(len(c.shards))]
+}
+
+func (c *shardedCache[K, V]) Set(key K, value V) bool {
+	return c.shard(key).Set(key, value)
+}
+
+func (c *shardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	return c.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (c *shardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *shardedCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+func (c *shardedCache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+func (c *shardedCache[K, V]) Len() int {
+	total := 0
+	for _, s := range c.shards {
+		total += s.Len()
+	}
+	return total
+}
+
+func (c *shardedCache[K, V]) OnEvict(fn EvictFunc[K, V]) {
+	for _, s := range c.shards {
+		s.OnEvict(fn)
+	}
+}
+
+func (c *shardedCache[K, V]) Stats() Stats {
+	var hits, misses, evictions, size int64
+	for _, s := range c.shards {
+		stats := s.Stats()
+		hits += stats.Hits
+		misses += stats.Misses
+		evictions += stats.Evictions
+		size += stats.Size
+	}
+	return newStats(hits, misses, evictions, size)
+}
+
+func (c *shardedCache[K, V]) Close() {
+	for _, s := range c.shards {
+		s.Close()
+	}
+}
+
+// Save writes items of all shards from the most recently used one, as Save of a single cache does.
+// Shards are copied one by one, so an item used during Save may keep its previous place.
+func (c *shardedCache[K, V]) Save(w io.Writer) error {
+	var entries []snapshotEntry[K, V]
+	for _, s := range c.shards {
+		entries = append(entries, s.snapshot()...)
+	}
+	slices.SortStableFunc(entries, func(a, b snapshotEntry[K, V]) int {
+		return cmp.Compare(b.used, a.used)
+	})
+	return writeSnapshot(w, c.shards[0].codec, entries)
+}
+
+func (c *shardedCache[K, V]) Load(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r, c.shards[0].codec)
+	if err != nil {
+		return err
+	}
+	byShard := make(map[*lruCache[K, V]][]snapshotEntry[K, V], len(c.shards))
+	for _, e := range entries {
+		// the clock is shared, so restored items of different shards keep their order too
+		e.used = c.shards[0].clock.backdate()
+		s := c.shard(e.Key)
+		byShard[s] = append(byShard[s], e)
+	}
+	for s, entries := range byShard {
+		s.restore(entries)
+	}
+	return nil
+}
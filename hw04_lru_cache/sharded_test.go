@@ -0,0 +1,135 @@
+package hw04lrucache
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestShardedCache(t *testing.T) {
+	t.Run("same api as Cache", func(t *testing.T) {
+		var c Cache = NewShardedCache(100, 8)
+
+		require.False(t, c.Set("aaa", 100))
+		require.True(t, c.Set("aaa", 300))
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+
+		c.Clear()
+		_, ok = c.Get("aaa")
+		require.False(t, ok)
+	})
+
+	t.Run("capacity is split between shards", func(t *testing.T) {
+		identity := func(key int) uint64 { return uint64(key) }
+		c := NewTypedShardedCache[int, int](4, 2, identity)
+		for i := range 6 {
+			c.Set(i, i) // even keys go to shard 0, odd keys to shard 1
+		}
+		require.Equal(t, 4, c.Len())
+		for key, expected := range map[int]bool{0: false, 1: false, 2: true, 3: true, 4: true, 5: true} {
+			_, ok := c.Get(key)
+			require.Equal(t, expected, ok, "key %d", key)
+		}
+
+		require.True(t, c.Delete(2))
+		stats := c.Stats()
+		require.Equal(t, Stats{Hits: 4, Misses: 2, Evictions: 2, Size: 3, HitRatio: 4.0 / 6}, stats)
+	})
+
+	t.Run("len never exceeds capacity", func(t *testing.T) {
+		for _, tc := range []struct{ capacity, shards int }{{100, 8}, {10, 64}, {7, 3}, {1, 16}, {64, 64}} {
+			c := NewShardedCache(tc.capacity, tc.shards)
+			for i := range 10 * tc.capacity {
+				c.Set(Key(strconv.Itoa(i)), i)
+				require.LessOrEqual(t, c.Len(), tc.capacity, "capacity %d, %d shards", tc.capacity, tc.shards)
+			}
+		}
+
+		identity := func(key int) uint64 { return uint64(key) }
+		c := NewTypedShardedCache[int, int](7, 3, identity)
+		for i := range 30 {
+			c.Set(i, i)
+		}
+		require.Equal(t, 7, c.Len(), "shards take 3, 2 and 2 items")
+	})
+
+	t.Run("options and hooks reach every shard", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		clock := newFakeClock()
+		c := NewShardedCache(100, 4, WithClock(clock.Now), WithDefaultTTL(time.Second), WithJanitor(time.Millisecond))
+		defer c.Close()
+
+		var mu sync.Mutex
+		expired := 0
+		c.OnEvict(func(_ Key, _ interface{}, reason EvictReason) {
+			mu.Lock()
+			defer mu.Unlock()
+			if reason == EvictExpired {
+				expired++
+			}
+		})
+		for i := range 20 {
+			c.Set(Key(strconv.Itoa(i)), i)
+		}
+		c.SetWithTTL("forever", 0, 0)
+
+		clock.Advance(time.Second)
+		require.Eventually(t, func() bool { return c.Len() == 1 }, time.Second, time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, 20, expired)
+	})
+}
+
+func BenchmarkCacheConcurrency(b *testing.B) {
+	const capacity = 10_000
+	const keys = 2 * capacity
+
+	caches := []struct {
+		name string
+		new  func() Cache
+	}{
+		{"single", func() Cache { return NewCache(capacity) }},
+		{"sharded16", func() Cache { return NewShardedCache(capacity, 16) }},
+		{"sharded64", func() Cache { return NewShardedCache(capacity, 64) }},
+	}
+	keyNames := make([]Key, keys)
+	for i := range keyNames {
+		keyNames[i] = Key(strconv.Itoa(i))
+	}
+
+	for _, cache := range caches {
+		for _, goroutines := range []int{1, 2, 4, 8, 16, 32, 64} {
+			b.Run(cache.name+"/goroutines="+strconv.Itoa(goroutines), func(b *testing.B) {
+				c := cache.new()
+				wg := sync.WaitGroup{}
+				wg.Add(goroutines)
+				b.ResetTimer()
+				for g := range goroutines {
+					go func() {
+						defer wg.Done()
+						rnd := rand.New(rand.NewSource(int64(g)))
+						// 80% reads and 20% writes of b.N operations in total
+						for i := g; i < b.N; i += goroutines {
+							key := keyNames[rnd.Intn(keys)]
+							if i%5 == 0 {
+								c.Set(key, i)
+							} else {
+								c.Get(key)
+							}
+						}
+					}()
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
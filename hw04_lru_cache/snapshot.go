@@ -0,0 +1,156 @@
+package hw04lrucache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var ErrInvalidSnapshot = errors.New("invalid cache snapshot")
+
+const snapshotVersion = 1
+
+// Codec turns a stream of snapshot records into bytes and back, see GobCodec and JSONCodec.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+type Encoder interface {
+	Encode(v any) error
+}
+
+type Decoder interface {
+	Decode(v any) error
+}
+
+var (
+	// GobCodec is the default codec. Concrete types stored in interface values
+	// must be registered with gob.Register.
+	GobCodec Codec = gobCodec{}
+	// JSONCodec writes human-readable snapshots, but interface values are restored
+	// as JSON decodes them into interface{}, e.g. numbers become float64.
+	JSONCodec Codec = jsonCodec{}
+)
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+// snapshotHeader starts a snapshot, it is followed by Len entries from the most recently used one.
+type snapshotHeader struct {
+	Version int
+	Len     int
+}
+
+type snapshotEntry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	ExpiresAt time.Time // zero if the item never expires
+
+	used int64 // accessClock time, it orders entries of different shards and is not saved
+}
+
+func writeSnapshot[K comparable, V any](w io.Writer, codec Codec, entries []snapshotEntry[K, V]) error {
+	enc := codec.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion, Len: len(entries)}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSnapshot[K comparable, V any](r io.Reader, codec Codec) ([]snapshotEntry[K, V], error) {
+	dec := codec.NewDecoder(r)
+	var h snapshotHeader
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidSnapshot, err)
+	}
+	if h.Version != snapshotVersion || h.Len < 0 {
+		return nil, fmt.Errorf("%w: version %d, %d entries", ErrInvalidSnapshot, h.Version, h.Len)
+	}
+
+	entries := make([]snapshotEntry[K, V], 0, min(h.Len, 1<<16))
+	for i := range h.Len {
+		var e snapshotEntry[K, V]
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidSnapshot, i, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Save writes live items from the most recently used one together with their expiration time.
+// The cache is locked only while items are copied, not while they are encoded.
+func (c *lruCache[K, V]) Save(w io.Writer) error {
+	return writeSnapshot(w, c.codec, c.snapshot())
+}
+
+// Load adds items saved by Save as less recently used than items already in cache,
+// keeping their order. Items that are expired, already in cache or don't fit are skipped,
+// so a smaller cache keeps the most recently used ones. Nothing is added if the snapshot is invalid.
+func (c *lruCache[K, V]) Load(r io.Reader) error {
+	entries, err := readSnapshot[K, V](r, c.codec)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		entries[i].used = c.clock.backdate()
+	}
+	c.restore(entries)
+	return nil
+}
+
+func (c *lruCache[K, V]) snapshot() []snapshotEntry[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entries := make([]snapshotEntry[K, V], 0, c.queue.Len())
+	for i := c.queue.Front(); i != nil; i = i.Next {
+		if c.expired(i, now) {
+			continue
+		}
+		item := i.Value
+		entries = append(entries, snapshotEntry[K, V]{
+			Key: item.key, Value: item.value, ExpiresAt: item.expiresAt, used: item.used,
+		})
+	}
+	return entries
+}
+
+func (c *lruCache[K, V]) restore(entries []snapshotEntry[K, V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for _, e := range entries {
+		if _, ok := c.items[e.Key]; ok {
+			continue
+		}
+		item := cacheItem[K, V]{key: e.Key, value: e.Value, weight: 1, expiresAt: e.ExpiresAt, used: e.used}
+		if c.weigher != nil {
+			item.weight = max(c.weigher(e.Key, e.Value), 0)
+		}
+		if !item.expiresAt.IsZero() && !now.Before(item.expiresAt) || c.weight+item.weight > c.maxWeight {
+			continue
+		}
+		c.items[e.Key] = c.queue.PushBack(item)
+		c.weight += item.weight
+		c.size.Add(1)
+	}
+}
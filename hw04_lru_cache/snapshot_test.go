@@ -0,0 +1,214 @@
+package hw04lrucache
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSnapshot(t *testing.T) {
+	codecs := []struct {
+		name  string
+		codec Codec
+	}{
+		{name: "gob", codec: GobCodec},
+		{name: "json", codec: JSONCodec},
+	}
+
+	for _, tc := range codecs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("recency order", func(t *testing.T) {
+				c := NewTypedCache[string, int](5, WithCodec(tc.codec))
+				for i, key := range []string{"a", "b", "c", "d", "e"} {
+					c.Set(key, i)
+				}
+				c.Get("a") // e d c b a -> a e d c b
+
+				var buf bytes.Buffer
+				require.NoError(t, c.Save(&buf))
+
+				restored := NewTypedCache[string, int](5, WithCodec(tc.codec))
+				require.NoError(t, restored.Load(&buf))
+				require.Equal(t, []string{"a", "e", "d", "c", "b"}, recency(restored))
+
+				val, ok := restored.Get("c")
+				require.True(t, ok)
+				require.Equal(t, 2, val)
+			})
+
+			t.Run("smaller cache keeps most recent", func(t *testing.T) {
+				c := NewTypedCache[string, int](5, WithCodec(tc.codec))
+				for i, key := range []string{"a", "b", "c", "d", "e"} {
+					c.Set(key, i)
+				}
+
+				var buf bytes.Buffer
+				require.NoError(t, c.Save(&buf))
+
+				restored := NewTypedCache[string, int](3, WithCodec(tc.codec))
+				var evicted []string
+				restored.OnEvict(func(key string, _ int, _ EvictReason) {
+					evicted = append(evicted, key)
+				})
+				require.NoError(t, restored.Load(&buf))
+				require.Equal(t, []string{"e", "d", "c"}, recency(restored))
+				require.Empty(t, evicted, "skipped items are never added")
+			})
+
+			t.Run("ttl", func(t *testing.T) {
+				clock := newFakeClock()
+				c := NewTypedCache[string, int](5, WithCodec(tc.codec), WithClock(clock.Now))
+				c.SetWithTTL("short", 1, time.Second)
+				c.SetWithTTL("long", 2, time.Minute)
+				c.Set("forever", 3)
+
+				var buf bytes.Buffer
+				require.NoError(t, c.Save(&buf))
+
+				clock.Advance(time.Second)
+				restored := NewTypedCache[string, int](5, WithCodec(tc.codec), WithClock(clock.Now))
+				require.NoError(t, restored.Load(&buf))
+				require.Equal(t, []string{"forever", "long"}, recency(restored), "expired item is skipped")
+
+				clock.Advance(time.Minute)
+				_, ok := restored.Get("long")
+				require.False(t, ok, "expiration time is kept")
+				_, ok = restored.Get("forever")
+				require.True(t, ok)
+			})
+		})
+	}
+
+	t.Run("existing items stay more recent", func(t *testing.T) {
+		c := NewTypedCache[string, int](3)
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Save(&buf))
+
+		restored := NewTypedCache[string, int](3)
+		restored.Set("a", 10)
+		restored.Set("c", 30)
+		require.NoError(t, restored.Load(&buf))
+		require.Equal(t, []string{"c", "a", "b"}, recency(restored))
+
+		val, _ := restored.Get("a")
+		require.Equal(t, 10, val, "value in cache is newer than the saved one")
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		weigher := func(_ string, value string) int64 { return int64(len(value)) }
+		c := NewWeightedCache[string, string](10, weigher)
+		c.Set("a", "aaaa")
+		c.Set("b", "bbbbb")
+		c.Set("c", "c")
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Save(&buf))
+
+		restored := NewWeightedCache[string, string](6, weigher)
+		require.NoError(t, restored.Load(&buf))
+		require.Equal(t, []string{"c", "b"}, recency(restored))
+		require.Equal(t, int64(6), restored.Weight())
+	})
+
+	t.Run("untyped cache", func(t *testing.T) {
+		c := NewCache(3)
+		c.Set("a", 1)
+		c.Set("b", "two")
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Save(&buf))
+
+		restored := NewCache(3)
+		require.NoError(t, restored.Load(&buf))
+		val, ok := restored.Get("a")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+		val, ok = restored.Get("b")
+		require.True(t, ok)
+		require.Equal(t, "two", val)
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		// the seed of NewShardedCache differs between caches, as it does between restarts
+		hash := func(key string) uint64 { return uint64(len(key)) }
+		c := NewTypedShardedCache[string, int](40, 4, hash)
+		for i := range 60 {
+			c.Set(strings.Repeat("k", i+1), i)
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Save(&buf))
+
+		restored := NewTypedShardedCache[string, int](40, 4, hash)
+		require.NoError(t, restored.Load(&buf))
+		require.Equal(t, c.Len(), restored.Len())
+		for i := range 60 {
+			key := strings.Repeat("k", i+1)
+			expected, inCache := c.Get(key)
+			val, ok := restored.Get(key)
+			require.Equal(t, inCache, ok)
+			require.Equal(t, expected, val)
+		}
+	})
+
+	t.Run("sharded keeps recency of the whole cache", func(t *testing.T) {
+		hash := func(key string) uint64 { return uint64(len(key)) }
+		c := NewTypedShardedCache[string, int](8, 4, hash)
+		for _, key := range []string{"a", "bb", "ccc", "dddd", "eeeee", "ffffff"} {
+			c.Set(key, len(key))
+		}
+		c.Get("bb")
+		c.Get("a")
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Save(&buf))
+		var saved bytes.Buffer
+		restored := NewTypedShardedCache[string, int](8, 4, hash)
+		require.NoError(t, restored.Load(io.TeeReader(&buf, &saved)))
+
+		// a single cache sees the order of the snapshot, shards have other capacity
+		single := NewTypedCache[string, int](8)
+		require.NoError(t, single.Load(&saved))
+		expected := []string{"a", "bb", "ffffff", "eeeee", "dddd", "ccc"}
+		require.Equal(t, expected, recency(single))
+
+		require.NoError(t, restored.Save(&buf))
+		single = NewTypedCache[string, int](8)
+		require.NoError(t, single.Load(&buf))
+		require.Equal(t, expected, recency(single), "restored shards keep the order")
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		c := NewTypedCache[string, int](3, WithCodec(JSONCodec))
+		c.Set("a", 1)
+		c.Set("b", 2)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.Save(&buf))
+		truncated := buf.String()[:buf.Len()-5]
+
+		restored := NewTypedCache[string, int](3, WithCodec(JSONCodec))
+		err := restored.Load(strings.NewReader(truncated))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+		require.Zero(t, restored.Len(), "nothing is loaded from an invalid snapshot")
+
+		err = restored.Load(strings.NewReader(`{"Version":2,"Len":0}`))
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+}
+
+// recency returns keys of c from the most recently used one.
+func recency[K comparable, V any](c LRU[K, V]) []K {
+	var keys []K
+	for _, e := range c.(*lruCache[K, V]).snapshot() {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
@@ -0,0 +1,49 @@
+package hw04lrucache
+
+import (
+	"fmt"
+)
+
+// EvictReason tells why an item left the cache.
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota + 1 // pushed out as the least recently used one
+	EvictExpired                         // time to live is over
+	EvictDeleted                         // removed by Delete
+	EvictCleared                         // removed by Clear
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictCleared:
+		return "cleared"
+	}
+	return fmt.Sprintf("EvictReason(%d)", int(r))
+}
+
+// EvictFunc is called for every item that left the cache.
+type EvictFunc[K comparable, V any] func(key K, value V, reason EvictReason)
+
+// Stats is a snapshot of cache counters, Evictions counts only items pushed out by capacity or expired.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int64
+	HitRatio  float64 // Hits / (Hits + Misses), 0 before the first Get
+}
+
+func newStats(hits, misses, evictions, size int64) Stats {
+	s := Stats{Hits: hits, Misses: misses, Evictions: evictions, Size: size}
+	if hits+misses > 0 {
+		s.HitRatio = float64(hits) / float64(hits+misses)
+	}
+	return s
+}
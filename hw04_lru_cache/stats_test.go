@@ -0,0 +1,101 @@
+package hw04lrucache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type evicted struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+func TestCacheOnEvict(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTypedCache[string, int](2, WithClock(clock.Now))
+	var got []evicted
+	c.OnEvict(func(key string, value int, reason EvictReason) {
+		got = append(got, evicted{key, value, reason})
+	})
+
+	c.Set("aaa", 1)
+	c.Set("bbb", 2)
+	c.Set("ccc", 3) // pushes "aaa" out
+	require.True(t, c.Delete("bbb"))
+	require.False(t, c.Delete("bbb"))
+	c.SetWithTTL("ddd", 4, time.Second)
+	clock.Advance(time.Second)
+	c.Get("ddd")
+	c.Set("eee", 5)
+	c.Clear()
+
+	require.Equal(t, []evicted{
+		{"aaa", 1, EvictCapacity},
+		{"bbb", 2, EvictDeleted},
+		{"ddd", 4, EvictExpired},
+		{"eee", 5, EvictCleared},
+		{"ccc", 3, EvictCleared},
+	}, got)
+	require.Equal(t, "capacity", EvictCapacity.String())
+	require.Equal(t, "EvictReason(0)", EvictReason(0).String())
+}
+
+func TestCacheOnEvictReentrant(t *testing.T) {
+	c := NewTypedCache[string, int](1)
+	c.OnEvict(func(key string, _ int, _ EvictReason) {
+		c.Get(key) // must not deadlock
+	})
+	c.Set("aaa", 1)
+	c.Set("bbb", 2)
+	require.Equal(t, 1, c.Len())
+}
+
+func TestCacheStats(t *testing.T) {
+	c := NewTypedCache[string, int](2)
+	require.Equal(t, Stats{}, c.Stats())
+
+	c.Set("aaa", 1)
+	c.Set("bbb", 2)
+	c.Set("ccc", 3)
+	c.Get("aaa")
+	c.Get("bbb")
+	c.Get("ccc")
+	c.Get("ccc")
+	c.Delete("ccc")
+
+	require.Equal(t, Stats{Hits: 3, Misses: 1, Evictions: 1, Size: 1, HitRatio: 0.75}, c.Stats())
+
+	c.Clear()
+	require.Equal(t, int64(0), c.Stats().Size)
+
+	t.Run("concurrent", func(t *testing.T) {
+		c := NewTypedCache[int, int](8000)
+		wg := sync.WaitGroup{}
+		wg.Add(8)
+		for g := range 8 {
+			go func() {
+				defer wg.Done()
+				for i := range 1000 {
+					c.Set(g*1000+i, i)
+					c.Get(g*1000 + i)
+					c.Get(-1)
+				}
+			}()
+		}
+		wg.Wait()
+		for i := range 100 {
+			c.Set(-i-1, i)
+		}
+
+		stats := c.Stats()
+		require.Equal(t, int64(8000), stats.Hits)
+		require.Equal(t, int64(8000), stats.Misses)
+		require.Equal(t, int64(100), stats.Evictions)
+		require.Equal(t, int64(8000), stats.Size)
+		require.InDelta(t, 0.5, stats.HitRatio, 1e-9)
+	})
+}
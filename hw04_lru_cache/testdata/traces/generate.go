@@ -0,0 +1,69 @@
+//go:build ignore
+
+// Generate writes the synthetic key traces used by the policy tests and benchmarks,
+// one key per line. The generator is seeded, so the output is the same on every run.
+//
+//	zipf.trace: 20000 lookups of 5000 keys with Zipf (s = 1.1) popularity, a skewed working set.
+//	scan.trace: 10 cycles of 400 random lookups in 200 hot keys followed by a scan of 1000 new keys,
+//	            while the scan runs every scanned key is followed by a hot lookup with probability 1/2.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := filepath.Join("testdata", "traces")
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	zipf := rand.NewZipf(rnd, 1.1, 1, 4999)
+	write(filepath.Join(dir, "zipf.trace"), func(emit func(key uint64)) {
+		for range 20_000 {
+			emit(zipf.Uint64())
+		}
+	})
+
+	write(filepath.Join(dir, "scan.trace"), func(emit func(key uint64)) {
+		const hot, scan = 200, 1000
+		next := uint64(hot) // scans use keys never seen before
+		for range 10 {
+			for range 2 * hot {
+				emit(uint64(rnd.Intn(hot)))
+			}
+			for range scan {
+				emit(next)
+				next++
+				if rnd.Intn(2) == 0 {
+					emit(uint64(rnd.Intn(hot)))
+				}
+			}
+		}
+	})
+}
+
+func write(path string, generate func(emit func(key uint64))) {
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	w := bufio.NewWriter(f)
+	generate(func(key uint64) {
+		fmt.Fprintf(w, "k%d\n", key)
+	})
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,107 @@
+package hw04lrucache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// fakeClock is a manually moved clock for deterministic expiration tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("lazy expiry on get", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCache[string, int](10, WithClock(clock.Now))
+
+		c.SetWithTTL("short", 1, time.Second)
+		c.SetWithTTL("long", 2, time.Minute)
+		c.Set("forever", 3)
+
+		clock.Advance(time.Second - 1)
+		_, ok := c.Get("short")
+		require.True(t, ok)
+
+		clock.Advance(1)
+		_, ok = c.Get("short")
+		require.False(t, ok)
+		require.Equal(t, 2, c.Len(), "expired item is removed by Get")
+
+		clock.Advance(time.Hour)
+		_, ok = c.Get("long")
+		require.False(t, ok)
+		val, ok := c.Get("forever")
+		require.True(t, ok)
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("default ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewCache(10, WithClock(clock.Now), WithDefaultTTL(time.Minute))
+
+		c.Set("aaa", 1)
+		clock.Advance(time.Minute)
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+	})
+
+	t.Run("set over expired item", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCache[string, int](10, WithClock(clock.Now))
+
+		c.SetWithTTL("aaa", 1, time.Second)
+		clock.Advance(time.Second)
+		require.False(t, c.SetWithTTL("aaa", 2, 0), "expired item was not in cache")
+		require.True(t, c.Set("aaa", 3))
+
+		clock.Advance(time.Hour)
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 3, val)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		clock := newFakeClock()
+		c := NewTypedCache[string, int](10, WithClock(clock.Now), WithJanitor(time.Millisecond))
+		defer c.Close()
+
+		c.SetWithTTL("aaa", 1, time.Second)
+		c.SetWithTTL("bbb", 2, time.Minute)
+		c.Set("ccc", 3)
+
+		clock.Advance(time.Second)
+		require.Eventually(t, func() bool { return c.Len() == 2 }, time.Second, time.Millisecond)
+
+		clock.Advance(time.Minute)
+		require.Eventually(t, func() bool { return c.Len() == 1 }, time.Second, time.Millisecond)
+
+		c.Close()
+		c.Close()
+		_, ok := c.Get("ccc")
+		require.True(t, ok, "cache is usable after Close")
+	})
+}
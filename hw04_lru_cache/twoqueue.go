@@ -0,0 +1,134 @@
+package hw04lrucache
+
+import (
+	"sync"
+)
+
+const (
+	twoQueueInRatio  = 0.25 // share of capacity for items seen once
+	twoQueueOutRatio = 0.5  // number of remembered evicted keys relative to capacity
+)
+
+// twoQueueCache is the full 2Q policy by Johnson and Shasha, "2Q: A Low Overhead High Performance
+// Buffer Management Replacement Algorithm" (A1in, A1out and Am there). New items go to the FIFO
+// recentIn, so a long scan of keys used once flushes only it. Hits in recentIn don't move an item:
+// re-references soon after the first one are usually correlated and tell nothing about popularity.
+// Keys evicted from recentIn are remembered in the ghost FIFO recentOut, and a key from recentOut
+// set again goes to the LRU frequent, the main working set. That is the only way into frequent.
+type twoQueueCache[K comparable, V any] struct {
+	mu sync.Mutex
+
+	capacity    int
+	inCapacity  int
+	outCapacity int
+
+	recentIn  TypedList[twoQueueItem[K, V]] // front is the newest
+	recentOut TypedList[K]                  // keys only
+	frequent  TypedList[twoQueueItem[K, V]] // front is the most recently used
+
+	items  map[K]*TypedListItem[twoQueueItem[K, V]] // items in recentIn and frequent
+	ghosts map[K]*TypedListItem[K]
+}
+
+type twoQueueItem[K comparable, V any] struct {
+	key    K
+	value  V
+	recent bool // the item is in recentIn
+}
+
+// NewTwoQueueCache creates a scan resistant 2Q cache that keeps up to capacity items, it is safe for concurrent use.
+func NewTwoQueueCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	c := &twoQueueCache[K, V]{
+		capacity:    capacity,
+		inCapacity:  max(int(float64(capacity)*twoQueueInRatio), 1),
+		outCapacity: max(int(float64(capacity)*twoQueueOutRatio), 1),
+	}
+	c.reset()
+	return c
+}
+
+func (c *twoQueueCache[K, V]) reset() {
+	c.recentIn, c.recentOut = NewTypedList[twoQueueItem[K, V]](), NewTypedList[K]()
+	c.frequent = NewTypedList[twoQueueItem[K, V]]()
+	c.items = make(map[K]*TypedListItem[twoQueueItem[K, V]], max(c.capacity, 0))
+	c.ghosts = make(map[K]*TypedListItem[K], c.outCapacity)
+}
+
+func (c *twoQueueCache[K, V]) Set(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, ok := c.items[key]; ok {
+		i.Value.value = value
+		c.touch(i)
+		return true
+	}
+	if c.capacity <= 0 {
+		return false
+	}
+
+	// the ghost is taken before reclaim, which could push it out of recentOut
+	ghost, wasGhost := c.ghosts[key]
+	if wasGhost {
+		c.recentOut.Remove(ghost)
+		delete(c.ghosts, key)
+	}
+	if len(c.items) >= c.capacity {
+		c.reclaim()
+	}
+	if wasGhost {
+		c.items[key] = c.frequent.PushFront(twoQueueItem[K, V]{key: key, value: value})
+		return false
+	}
+	c.items[key] = c.recentIn.PushFront(twoQueueItem[K, V]{key: key, value: value, recent: true})
+	return false
+}
+
+func (c *twoQueueCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.touch(i)
+	return i.Value.value, true
+}
+
+// touch moves a used item of frequent to its front, items of recentIn stay where they are.
+func (c *twoQueueCache[K, V]) touch(i *TypedListItem[twoQueueItem[K, V]]) {
+	if !i.Value.recent {
+		c.frequent.MoveToFront(i)
+	}
+}
+
+func (c *twoQueueCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reset()
+}
+
+// reclaim frees one place, recentIn gives it up while it is over its share.
+func (c *twoQueueCache[K, V]) reclaim() {
+	if c.recentIn.Len() > c.inCapacity || c.frequent.Len() == 0 {
+		last := c.recentIn.Back()
+		c.recentIn.Remove(last)
+		key := last.Value.key
+		delete(c.items, key)
+
+		c.ghosts[key] = c.recentOut.PushFront(key)
+		if c.recentOut.Len() > c.outCapacity {
+			oldest := c.recentOut.Back()
+			c.recentOut.Remove(oldest)
+			delete(c.ghosts, oldest.Value)
+		}
+		return
+	}
+
+	last := c.frequent.Back()
+	c.frequent.Remove(last)
+	delete(c.items, last.Value.key)
+}
@@ -0,0 +1,80 @@
+package hw04lrucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedCache(t *testing.T) {
+	byLen := func(_ string, value []byte) int64 { return int64(len(value)) }
+
+	t.Run("evicts until the new item fits", func(t *testing.T) {
+		c := NewWeightedCache[string, []byte](10, byLen)
+		var evicted []string
+		c.OnEvict(func(key string, _ []byte, reason EvictReason) {
+			require.Equal(t, EvictCapacity, reason)
+			evicted = append(evicted, key)
+		})
+
+		c.Set("a", make([]byte, 4))
+		c.Set("b", make([]byte, 3))
+		c.Set("c", make([]byte, 2))
+		c.Get("a") // [a, c, b]
+		require.Equal(t, int64(9), c.Weight())
+
+		c.Set("d", make([]byte, 5)) // b and c must go to free 4
+		require.Equal(t, []string{"b", "c"}, evicted)
+		require.Equal(t, int64(9), c.Weight())
+		require.Equal(t, 2, c.Len())
+	})
+
+	t.Run("update changes weight", func(t *testing.T) {
+		c := NewWeightedCache[string, []byte](10, byLen)
+		c.Set("a", make([]byte, 4))
+		c.Set("b", make([]byte, 4))
+		require.True(t, c.Set("b", make([]byte, 6))) // [b, a] weight 10
+		require.Equal(t, int64(10), c.Weight())
+
+		require.True(t, c.Set("b", make([]byte, 7))) // a is evicted
+		require.Equal(t, int64(7), c.Weight())
+		_, ok := c.Get("a")
+		require.False(t, ok)
+	})
+
+	t.Run("too heavy item", func(t *testing.T) {
+		c := NewWeightedCache[string, []byte](10, byLen)
+		c.Set("a", make([]byte, 4))
+		c.Set("b", make([]byte, 4))
+
+		wasInCache, err := c.TrySet("huge", make([]byte, 11))
+		require.ErrorIs(t, err, ErrTooHeavy)
+		require.False(t, wasInCache)
+		require.Equal(t, 2, c.Len(), "nothing is evicted for an item that can't fit")
+
+		wasInCache, err = c.TrySet("a", make([]byte, 11))
+		require.ErrorIs(t, err, ErrTooHeavy)
+		require.True(t, wasInCache)
+		_, ok := c.Get("a")
+		require.False(t, ok, "the old value is not kept")
+
+		require.False(t, c.Set("huge", make([]byte, 11)))
+		require.Equal(t, int64(4), c.Weight())
+	})
+
+	t.Run("count based cache", func(t *testing.T) {
+		c := NewTypedCache[string, int](0)
+		require.False(t, c.Set("a", 1))
+		require.Equal(t, 0, c.Len())
+	})
+
+	t.Run("clear resets weight", func(t *testing.T) {
+		c := NewWeightedCache[string, []byte](10, byLen)
+		c.Set("a", make([]byte, 4))
+		c.Clear()
+		require.Equal(t, int64(0), c.Weight())
+		wasInCache, err := c.TrySet("a", make([]byte, 10))
+		require.NoError(t, err)
+		require.False(t, wasInCache)
+	})
+}
@@ -0,0 +1,72 @@
+package hw05parallelexecution
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// TaskError is the error of the task at Index in the tasks slice.
+type TaskError struct {
+	Index int
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// Report tells what has happened to every task of a run, tasks are identified by their indexes.
+type Report struct {
+	Failed    []TaskError // ordered by index
+	Succeeded []int
+	Skipped   []int // nil tasks and tasks not started because the run was stopped
+}
+
+func newReport(statuses []taskStatus, failed []TaskError) Report {
+	r := Report{Failed: failed}
+	slices.SortFunc(r.Failed, func(a, b TaskError) int { return a.Index - b.Index })
+	for i, status := range statuses {
+		switch status {
+		case taskSucceeded:
+			r.Succeeded = append(r.Succeeded, i)
+		case taskSkipped:
+			r.Skipped = append(r.Skipped, i)
+		case taskFailed:
+		}
+	}
+	return r
+}
+
+// RunError is returned when the run has been stopped by the errors limit or the context.
+// It wraps ErrErrorsLimitExceeded and the context error, whichever have stopped the run,
+// and a *TaskError for every failed task, so errors.Is and errors.As see all of them.
+type RunError struct {
+	Report
+	causes []error
+}
+
+func (e *RunError) Error() string {
+	return errors.Join(e.Unwrap()...).Error()
+}
+
+func (e *RunError) Unwrap() []error {
+	errs := make([]error, 0, len(e.causes)+len(e.Failed))
+	errs = append(errs, e.causes...)
+	for i := range e.Failed {
+		errs = append(errs, &e.Failed[i])
+	}
+	return errs
+}
+
+type taskStatus int
+
+const (
+	taskSkipped taskStatus = iota
+	taskSucceeded
+	taskFailed
+)
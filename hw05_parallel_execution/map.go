@@ -0,0 +1,38 @@
+package hw05parallelexecution
+
+import (
+	"context"
+)
+
+// Map calls fn for every input in workers goroutines and returns results in the order of inputs.
+// It stops like RunContext does with maxErrors as m, and returns the same *RunError, where task
+// indexes are indexes of inputs. As in Run, errors below the limit are not returned.
+// Results of failed and skipped inputs are zero values, MapReport tells which inputs they are.
+func Map[T, R any](
+	ctx context.Context, inputs []T, fn func(ctx context.Context, input T) (R, error), workers, maxErrors int,
+) ([]R, error) {
+	results, _, err := MapReport(ctx, inputs, fn, workers, maxErrors)
+	return results, err
+}
+
+// MapReport is Map that also reports the outcome of every input, as RunReport does for tasks.
+func MapReport[T, R any](
+	ctx context.Context, inputs []T, fn func(ctx context.Context, input T) (R, error), workers, maxErrors int,
+) ([]R, Report, error) {
+	results := make([]R, len(inputs))
+	tasks := make([]ContextTask, len(inputs))
+	for i, input := range inputs {
+		// every task writes only its own result, they are read after RunReport returns
+		tasks[i] = func(ctx context.Context) error {
+			result, err := fn(ctx, input)
+			if err != nil {
+				return err
+			}
+			results[i] = result
+			return nil
+		}
+	}
+
+	report, err := RunReport(ctx, tasks, workers, maxErrors)
+	return results, report, err
+}
@@ -0,0 +1,98 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMap(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("results in input order", func(t *testing.T) {
+		inputs := make([]int, 100)
+		for i := range inputs {
+			inputs[i] = i
+		}
+		square := func(_ context.Context, x int) (int, error) {
+			// later inputs finish first
+			time.Sleep(time.Duration(len(inputs)-x) * time.Microsecond)
+			return x * x, nil
+		}
+
+		results, err := Map(context.Background(), inputs, square, 10, 1)
+		require.NoError(t, err)
+		for i, result := range results {
+			require.Equal(t, i*i, result)
+		}
+	})
+
+	t.Run("another result type", func(t *testing.T) {
+		atoi := func(_ context.Context, s string) (int, error) {
+			return strconv.Atoi(s)
+		}
+
+		results, err := Map(context.Background(), []string{"1", "x", "3"}, atoi, 1, 5)
+		require.NoError(t, err, "errors below the limit are not returned, as in Run")
+		require.Equal(t, []int{1, 0, 3}, results, "failed input has zero result")
+
+		results, report, err := MapReport(context.Background(), []string{"1", "x", "0"}, atoi, 1, 5)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0, 0}, results)
+		require.Len(t, report.Failed, 1, "failed input is told from zero result")
+		require.Equal(t, 1, report.Failed[0].Index)
+		require.ErrorAs(t, report.Failed[0].Err, new(*strconv.NumError))
+		require.Equal(t, []int{0, 2}, report.Succeeded)
+
+		results, err = Map(context.Background(), []string{"1", "x", "3"}, atoi, 1, 1)
+		var numErr *strconv.NumError
+		require.ErrorAs(t, err, &numErr)
+		var runErr *RunError
+		require.ErrorAs(t, err, &runErr)
+		require.Len(t, runErr.Failed, 1)
+		require.Equal(t, 1, runErr.Failed[0].Index)
+		require.Equal(t, []int{1, 0, 0}, results)
+	})
+
+	t.Run("errors limit exceeded", func(t *testing.T) {
+		errOdd := errors.New("odd")
+		inputs := []int{2, 1, 4, 3, 6, 8}
+		results, err := Map(context.Background(), inputs, func(_ context.Context, x int) (int, error) {
+			if x%2 == 1 {
+				return 0, errOdd
+			}
+			return x / 2, nil
+		}, 1, 2)
+
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.ErrorIs(t, err, errOdd)
+		var runErr *RunError
+		require.ErrorAs(t, err, &runErr)
+		require.Equal(t, []int{0, 2}, runErr.Succeeded)
+		require.Equal(t, []int{4, 5}, runErr.Skipped)
+		require.Equal(t, []int{1, 0, 2, 0, 0, 0}, results)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := Map(ctx, []int{1, 2, 3}, func(_ context.Context, x int) (int, error) {
+			return x, nil
+		}, 2, 0)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []int{0, 0, 0}, results)
+	})
+
+	t.Run("0 workers", func(t *testing.T) {
+		_, err := Map(context.Background(), []int{1}, func(_ context.Context, x int) (int, error) {
+			return x, nil
+		}, 0, 0)
+		require.ErrorIs(t, err, ErrWorkersCountLow)
+	})
+}
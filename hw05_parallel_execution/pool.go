@@ -0,0 +1,205 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrPoolClosed = errors.New("pool is shut down")
+	ErrQueueFull  = errors.New("pool queue is full")
+)
+
+// Future is the result of a task submitted to a Pool.
+type Future interface {
+	// Done is closed when the task has finished or has been skipped.
+	Done() <-chan struct{}
+	// Err returns the task error after Done is closed, and nil before that.
+	// A task skipped because the errors limit was exceeded gets ErrErrorsLimitExceeded.
+	Err() error
+	// Wait waits for the task and returns its error, or ctx.Err() if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// Pool runs submitted tasks in a fixed number of long-lived workers.
+// Like Run, it stops running tasks once m of them have failed, m <= 0 means errors are ignored.
+type Pool struct {
+	maxErrors int
+	reject    bool
+	queueSize int
+
+	queue chan *future
+	mu    sync.RWMutex // Submit sends to queue under the read lock, so it is not closed meanwhile
+
+	errorsCount atomic.Int64
+	broken      chan struct{} // closed when the errors limit is exceeded
+	brokenOnce  sync.Once
+
+	closing     chan struct{} // closed when Shutdown is called, it releases blocked Submit calls
+	closingOnce sync.Once
+	workers     sync.WaitGroup
+	stopped     chan struct{} // closed when all workers are done
+}
+
+type PoolOption func(p *Pool)
+
+// WithQueueSize sets how many submitted tasks may wait for a worker, it is n by default.
+func WithQueueSize(size int) PoolOption {
+	return func(p *Pool) {
+		p.queueSize = max(size, 0)
+	}
+}
+
+// WithRejectWhenFull makes Submit return ErrQueueFull instead of waiting for room in the queue.
+func WithRejectWhenFull() PoolOption {
+	return func(p *Pool) {
+		p.reject = true
+	}
+}
+
+// NewPool starts n workers. The pool must be shut down to stop them.
+func NewPool(n, m int, opts ...PoolOption) (*Pool, error) {
+	if n <= 0 {
+		return nil, ErrWorkersCountLow
+	}
+	p := &Pool{
+		maxErrors: max(m, 0),
+		queueSize: n,
+		broken:    make(chan struct{}),
+		closing:   make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.queue = make(chan *future, p.queueSize)
+
+	p.workers.Add(n)
+	for range n {
+		go p.worker()
+	}
+	go func() {
+		p.workers.Wait()
+		close(p.stopped)
+	}()
+	return p, nil
+}
+
+// Submit queues task and returns its Future. When the queue is full, Submit waits for room
+// or returns ErrQueueFull if WithRejectWhenFull is set. After the errors limit is exceeded
+// it returns ErrErrorsLimitExceeded, after Shutdown it returns ErrPoolClosed. A nil task is skipped.
+func (p *Pool) Submit(task Task) (Future, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	select {
+	case <-p.closing:
+		return nil, ErrPoolClosed
+	case <-p.broken:
+		return nil, ErrErrorsLimitExceeded
+	default:
+	}
+
+	f := &future{task: task, done: make(chan struct{})}
+	if task == nil {
+		close(f.done)
+		return f, nil
+	}
+
+	if p.reject {
+		select {
+		case p.queue <- f:
+			return f, nil
+		default:
+			return nil, ErrQueueFull
+		}
+	}
+	select {
+	case p.queue <- f:
+		return f, nil
+	case <-p.closing:
+		return nil, ErrPoolClosed
+	case <-p.broken:
+		return nil, ErrErrorsLimitExceeded
+	}
+}
+
+// Shutdown stops accepting tasks and waits until the queued ones are done. If ctx is done first,
+// it returns ctx.Err() and workers finish the queue in background. Tasks left in the queue after
+// the errors limit is exceeded are skipped, and Shutdown returns ErrErrorsLimitExceeded then.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.closingOnce.Do(func() {
+		close(p.closing)
+
+		p.mu.Lock()
+		close(p.queue)
+		p.mu.Unlock()
+	})
+
+	select {
+	case <-p.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-p.broken:
+		return ErrErrorsLimitExceeded
+	default:
+		return nil
+	}
+}
+
+func (p *Pool) worker() {
+	defer p.workers.Done()
+
+	for f := range p.queue {
+		select {
+		case <-p.broken:
+			f.finish(ErrErrorsLimitExceeded)
+			continue
+		default:
+		}
+
+		err := f.task()
+		if err != nil && p.maxErrors > 0 && p.errorsCount.Add(1) >= int64(p.maxErrors) {
+			p.brokenOnce.Do(func() { close(p.broken) })
+		}
+		f.finish(err)
+	}
+}
+
+type future struct {
+	task Task
+	done chan struct{}
+	err  error
+}
+
+func (f *future) finish(err error) {
+	f.err = err
+	close(f.done)
+}
+
+func (f *future) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+func (f *future) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,211 @@
+package hw05parallelexecution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// blockingTask returns a task that waits for release and a channel closed when the task starts.
+func blockingTask(release <-chan struct{}) (Task, <-chan struct{}) {
+	started := make(chan struct{})
+	return func() error {
+		close(started)
+		<-release
+		return nil
+	}, started
+}
+
+func TestPool(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("runs submitted tasks", func(t *testing.T) {
+		p, err := NewPool(4, 0)
+		require.NoError(t, err)
+
+		errTask := errors.New("task failed")
+		var runTasksCount atomic.Int32
+		futures := make([]Future, 0, 20)
+		for i := range 20 {
+			f, err := p.Submit(func() error {
+				runTasksCount.Add(1)
+				if i%2 == 1 {
+					return errTask
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			futures = append(futures, f)
+		}
+
+		for i, f := range futures {
+			err := f.Wait(context.Background())
+			if i%2 == 1 {
+				require.ErrorIs(t, err, errTask)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, err, f.Err())
+		}
+		require.NoError(t, p.Shutdown(context.Background()))
+		require.Equal(t, int32(20), runTasksCount.Load())
+
+		_, err = p.Submit(func() error { return nil })
+		require.ErrorIs(t, err, ErrPoolClosed)
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		p, err := NewPool(1, 0, WithQueueSize(1))
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		task, started := blockingTask(release)
+		_, err = p.Submit(task)
+		require.NoError(t, err)
+		<-started
+		_, err = p.Submit(func() error { return nil }) // waits in the queue
+		require.NoError(t, err)
+
+		submitted := make(chan error, 1)
+		go func() {
+			_, err := p.Submit(func() error { return nil })
+			submitted <- err
+		}()
+		select {
+		case <-submitted:
+			t.Fatal("Submit does not wait for room in the full queue")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-submitted)
+		require.NoError(t, p.Shutdown(context.Background()))
+	})
+
+	t.Run("reject when full", func(t *testing.T) {
+		p, err := NewPool(1, 0, WithQueueSize(1), WithRejectWhenFull())
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		task, started := blockingTask(release)
+		_, err = p.Submit(task)
+		require.NoError(t, err)
+		<-started
+		_, err = p.Submit(func() error { return nil })
+		require.NoError(t, err)
+
+		_, err = p.Submit(func() error { return nil })
+		require.ErrorIs(t, err, ErrQueueFull)
+
+		close(release)
+		require.NoError(t, p.Shutdown(context.Background()))
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		p, err := NewPool(1, 2, WithQueueSize(10))
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		errTask := errors.New("task failed")
+		task, started := blockingTask(release)
+		_, err = p.Submit(task)
+		require.NoError(t, err)
+		<-started
+
+		futures := make([]Future, 0, 5)
+		var runTasksCount atomic.Int32
+		for range 5 {
+			f, err := p.Submit(func() error {
+				runTasksCount.Add(1)
+				return errTask
+			})
+			require.NoError(t, err)
+			futures = append(futures, f)
+		}
+		close(release)
+
+		for i, f := range futures {
+			err := f.Wait(context.Background())
+			if i < 2 {
+				require.ErrorIs(t, err, errTask)
+			} else {
+				require.ErrorIs(t, err, ErrErrorsLimitExceeded, "task must be skipped")
+			}
+		}
+		require.Equal(t, int32(2), runTasksCount.Load())
+
+		_, err = p.Submit(func() error { return nil })
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.ErrorIs(t, p.Shutdown(context.Background()), ErrErrorsLimitExceeded)
+	})
+
+	t.Run("shutdown drains the queue", func(t *testing.T) {
+		p, err := NewPool(2, 1, WithQueueSize(50))
+		require.NoError(t, err)
+
+		tasks, runTasksCount := produceSleepTasks(50)
+		for _, task := range tasks {
+			_, err := p.Submit(task)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, p.Shutdown(context.Background()))
+		require.Equal(t, int32(50), runTasksCount.Load(), "not all tasks were completed")
+		require.NoError(t, p.Shutdown(context.Background()), "second shutdown")
+	})
+
+	t.Run("shutdown deadline", func(t *testing.T) {
+		p, err := NewPool(1, 0)
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		task, started := blockingTask(release)
+		f, err := p.Submit(task)
+		require.NoError(t, err)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, p.Shutdown(ctx), context.DeadlineExceeded)
+		require.Nil(t, f.Err(), "task is not done yet")
+
+		close(release)
+		require.NoError(t, p.Shutdown(context.Background()))
+		require.NoError(t, f.Wait(context.Background()))
+	})
+
+	t.Run("shutdown releases waiting submit", func(t *testing.T) {
+		p, err := NewPool(1, 0, WithQueueSize(0))
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		task, started := blockingTask(release)
+		_, err = p.Submit(task)
+		require.NoError(t, err)
+		<-started
+
+		submitErr := make(chan error)
+		go func() {
+			_, err := p.Submit(func() error { return nil })
+			submitErr <- err
+		}()
+		shutdownErr := make(chan error)
+		go func() {
+			shutdownErr <- p.Shutdown(context.Background())
+		}()
+
+		require.ErrorIs(t, <-submitErr, ErrPoolClosed)
+		close(release)
+		require.NoError(t, <-shutdownErr)
+	})
+
+	t.Run("0 workers", func(t *testing.T) {
+		_, err := NewPool(0, 0)
+		require.ErrorIs(t, err, ErrWorkersCountLow)
+	})
+}
@@ -1,8 +1,10 @@
 package hw05parallelexecution
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -12,65 +14,154 @@ var (
 
 type Task func() error
 
-func WorkerChan(wg *sync.WaitGroup, tasksQueue <-chan Task, errorsQueue chan<- struct{}, ignoreErrors bool) {
+// ContextTask is a task that should stop when its context is canceled.
+type ContextTask func(ctx context.Context) error
+
+// WorkerChan runs tasks until tasksQueue is closed or ctx is canceled. Every task error is sent
+// to errorsQueue, which must have room for all of them. When maxErrors > 0 errors are collected,
+// the worker cancels the run with ErrErrorsLimitExceeded as the cause, maxErrors <= 0 means no limit.
+func WorkerChan(
+	ctx context.Context, cancel context.CancelCauseFunc,
+	wg *sync.WaitGroup, tasksQueue <-chan ContextTask, errorsQueue chan<- error, maxErrors int,
+) {
 	defer wg.Done()
-	var taskResult error
 	for task := range tasksQueue {
+		if ctx.Err() != nil {
+			return
+		}
 		if task == nil {
 			continue
 		}
-		taskResult = task()
-		if taskResult != nil {
-			select {
-			case errorsQueue <- struct{}{}:
-			default:
-				if !ignoreErrors {
-					return
-				}
-			}
+		err := task(ctx)
+		if err == nil {
+			continue
+		}
+		errorsQueue <- err
+		if maxErrors > 0 && len(errorsQueue) >= maxErrors {
+			cancel(ErrErrorsLimitExceeded)
+			return
 		}
 	}
 }
 
-func TasksDispatcher(inboundTasks []Task, tasksQueue chan<- Task, closeSignal <-chan struct{}) {
+func wrapTasks(inboundTasks []Task) []ContextTask {
+	tasks := make([]ContextTask, len(inboundTasks))
+	for i, task := range inboundTasks {
+		if task != nil {
+			tasks[i] = func(context.Context) error { return task() }
+		}
+	}
+	return tasks
+}
+
+func TasksDispatcher(ctx context.Context, inboundTasks []ContextTask, tasksQueue chan<- ContextTask) {
 	defer close(tasksQueue)
 
 	for _, newTask := range inboundTasks {
 		select {
-		case <-closeSignal:
+		case <-ctx.Done():
 			return
 		case tasksQueue <- newTask:
 		}
 	}
 }
 
+// Run runs tasks in n goroutines and stops after m tasks have failed, m <= 0 means errors are ignored.
+// Failed tasks are not reported by the error unless they stop the run: fewer than m failures, or any
+// number of them with m <= 0, give nil. After m failures Run returns a *RunError wrapping
+// ErrErrorsLimitExceeded. RunReport tells which tasks have failed in both cases.
 func Run(inboundTasks []Task, n, m int) error {
-	tasksQueue := make(chan Task, len(inboundTasks))
+	_, err := RunReport(context.Background(), wrapTasks(inboundTasks), n, m)
+	return err
+}
+
+// RunContext is Run for tasks that get a context. When ctx is canceled no new tasks are started.
+// If that has skipped tasks or ctx is done by the end of a task, the returned *RunError wraps ctx.Err() too.
+func RunContext(ctx context.Context, inboundTasks []ContextTask, n, m int) error {
+	_, err := RunReport(ctx, inboundTasks, n, m)
+	return err
+}
+
+// RunReport is RunContext that also reports the outcome of every task, whether the run has been
+// stopped or not. The report of a stopped run is also available as the Report of its *RunError.
+func RunReport(ctx context.Context, inboundTasks []ContextTask, n, m int) (Report, error) {
 	// There is no point in running anything if the number of workers is 0, so return error of invalid parameter
 	if n <= 0 {
-		return ErrWorkersCountLow
+		return Report{}, ErrWorkersCountLow
 	}
-	var ignoreErrors bool
-	if m <= 0 {
-		ignoreErrors = true
-		m = 0
+	m = max(m, 0)
+
+	// every task writes only its own status, they are read after all workers are done
+	statuses := make([]taskStatus, len(inboundTasks))
+	tasks := make([]ContextTask, len(inboundTasks))
+	// interrupted is set when ctx is done by the end of a task, so the task may have been cut short
+	var interrupted atomic.Bool
+	for i, task := range inboundTasks {
+		if task == nil {
+			continue
+		}
+		tasks[i] = func(taskCtx context.Context) error {
+			err := task(taskCtx)
+			if ctx.Err() != nil {
+				interrupted.Store(true)
+			}
+			if err != nil {
+				statuses[i] = taskFailed
+				return &TaskError{Index: i, Err: err}
+			}
+			statuses[i] = taskSucceeded
+			return nil
+		}
 	}
 
-	errorsQueue := make(chan struct{}, m)
-	closeSignal := make(chan struct{})
+	// runCtx is also canceled when the errors limit is reached, so running tasks can stop early
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	tasksQueue := make(chan ContextTask)
+	errorsQueue := make(chan error, len(tasks))
 	var wg sync.WaitGroup
 	wg.Add(n)
 
-	go TasksDispatcher(inboundTasks, tasksQueue, closeSignal)
+	dispatched := make(chan struct{})
+	go func() {
+		defer close(dispatched)
+		TasksDispatcher(runCtx, tasks, tasksQueue)
+	}()
 	for range n {
-		go WorkerChan(&wg, tasksQueue, errorsQueue, ignoreErrors)
+		go WorkerChan(runCtx, cancel, &wg, tasksQueue, errorsQueue, m)
 	}
 	wg.Wait()
-	close(closeSignal)
+	// workers stop only when the queue is closed or runCtx is canceled, so the dispatcher is done too
+	cancel(nil)
+	<-dispatched
 	close(errorsQueue)
 
-	if m > 0 && len(errorsQueue) == m {
-		return ErrErrorsLimitExceeded
+	failed := make([]TaskError, 0, len(errorsQueue))
+	for err := range errorsQueue {
+		failed = append(failed, *err.(*TaskError))
+	}
+	report := newReport(statuses, failed)
+	var causes []error
+	// ctx done after the last task has finished has stopped nothing
+	if ctx.Err() != nil && (interrupted.Load() || skippedTasks(inboundTasks, report.Skipped)) {
+		causes = append(causes, ctx.Err())
+	}
+	if m > 0 && len(failed) >= m {
+		causes = append(causes, ErrErrorsLimitExceeded)
+	}
+	if len(causes) > 0 {
+		return report, &RunError{Report: report, causes: causes}
+	}
+	return report, nil
+}
+
+// skippedTasks tells whether any of the skipped tasks is not nil, so it has not run because of a stop.
+func skippedTasks(tasks []ContextTask, skipped []int) bool {
+	for _, i := range skipped {
+		if tasks[i] != nil {
+			return true
+		}
 	}
-	return nil
+	return false
 }
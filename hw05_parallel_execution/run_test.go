@@ -1,6 +1,7 @@
 package hw05parallelexecution
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -231,6 +232,199 @@ func TestRunAdditional(t *testing.T) {
 	})
 }
 
+func TestRunContext(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("cancel stops dispatching", func(t *testing.T) {
+		tasksCount := 10
+		workersCount := 2
+		var startedCount atomic.Int32
+		tasks := make([]ContextTask, 0, tasksCount)
+		for range tasksCount {
+			tasks = append(tasks, func(ctx context.Context) error {
+				startedCount.Add(1)
+				<-ctx.Done()
+				return nil
+			})
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- RunContext(ctx, tasks, workersCount, 0)
+		}()
+		require.Eventually(t, func() bool {
+			return startedCount.Load() == int32(workersCount)
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		err := <-done
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, int32(workersCount), startedCount.Load(), "tasks were started after cancel")
+	})
+
+	t.Run("deadline with task errors", func(t *testing.T) {
+		tasks := make([]ContextTask, 0, 4)
+		for range 4 {
+			tasks = append(tasks, func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := RunContext(ctx, tasks, 4, 10)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotErrorIs(t, err, ErrErrorsLimitExceeded)
+
+		var runErr *RunError
+		require.ErrorAs(t, err, &runErr)
+		require.Len(t, runErr.Failed, 4)
+		for i, taskErr := range runErr.Failed {
+			require.Equal(t, i, taskErr.Index)
+			require.ErrorIs(t, taskErr.Err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("tasks fail on cancel", func(t *testing.T) {
+		errAborted := errors.New("aborted")
+		tasks := make([]ContextTask, 0, 4)
+		for range 4 {
+			tasks = append(tasks, func(ctx context.Context) error {
+				<-ctx.Done()
+				return errAborted
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := RunContext(ctx, tasks, 4, 2)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded, "task errors have reached the limit too")
+		require.ErrorIs(t, err, errAborted)
+	})
+
+	t.Run("errors limit cancels running tasks", func(t *testing.T) {
+		errTask := errors.New("task failed")
+		var cause error
+		started := make(chan struct{})
+		tasks := []ContextTask{
+			func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				cause = context.Cause(ctx)
+				return nil
+			},
+			func(context.Context) error {
+				<-started
+				return errTask
+			},
+			func(context.Context) error {
+				t.Error("task is started after the errors limit")
+				return nil
+			},
+		}
+
+		err := RunContext(context.Background(), tasks, 2, 1)
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.ErrorIs(t, cause, ErrErrorsLimitExceeded)
+	})
+
+	t.Run("already canceled", func(t *testing.T) {
+		tasks, runTasksCount := produceFibonacciTasks(10, 20, 0)
+		contextTasks := make([]ContextTask, 0, len(tasks))
+		for _, task := range tasks {
+			contextTasks = append(contextTasks, func(context.Context) error { return task() })
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := RunContext(ctx, contextTasks, 3, 1)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, int32(0), runTasksCount.Load(), "no tasks must be completed")
+	})
+
+	t.Run("deadline after the last task", func(t *testing.T) {
+		// the deadline passes once the only task has finished, before the run checks ctx
+		ctx := &expiringContext{Context: context.Background(), validChecks: 1}
+		report, err := RunReport(ctx, []ContextTask{func(context.Context) error { return nil }}, 1, 0)
+		require.NoError(t, err)
+		require.Equal(t, []int{0}, report.Succeeded)
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+}
+
+// expiringContext is a context whose deadline passes after validChecks calls of Err. It is never done,
+// so only the code that checks Err sees the deadline.
+type expiringContext struct {
+	context.Context
+	validChecks int32
+	checks      atomic.Int32
+}
+
+func (c *expiringContext) Err() error {
+	if c.checks.Add(1) > c.validChecks {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+func TestRunErrors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+	// a single worker runs tasks in order, so the sets are known exactly
+	tasks := []Task{
+		func() error { return nil },
+		func() error { return errFirst },
+		nil,
+		func() error { return errSecond },
+		func() error { return nil },
+	}
+
+	t.Run("errors limit exceeded", func(t *testing.T) {
+		err := Run(tasks, 1, 2)
+		require.ErrorIs(t, err, ErrErrorsLimitExceeded)
+		require.ErrorIs(t, err, errFirst)
+		require.ErrorIs(t, err, errSecond)
+
+		var runErr *RunError
+		require.ErrorAs(t, err, &runErr)
+		require.Equal(t, []TaskError{{Index: 1, Err: errFirst}, {Index: 3, Err: errSecond}}, runErr.Failed)
+		require.Equal(t, []int{0}, runErr.Succeeded)
+		require.Equal(t, []int{2, 4}, runErr.Skipped)
+
+		var taskErr *TaskError
+		require.ErrorAs(t, err, &taskErr)
+		require.Equal(t, 1, taskErr.Index)
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		require.True(t, ok, "error is not compatible with errors.Join")
+		require.Len(t, joined.Unwrap(), 3)
+		require.Equal(t, "errors limit exceeded\ntask 1: first\ntask 3: second", err.Error())
+	})
+
+	t.Run("errors below limit", func(t *testing.T) {
+		require.NoError(t, Run(tasks, 1, 3))
+
+		report, err := RunReport(context.Background(), wrapTasks(tasks), 1, 3)
+		require.NoError(t, err)
+		require.Equal(t, []TaskError{{Index: 1, Err: errFirst}, {Index: 3, Err: errSecond}}, report.Failed)
+		require.Equal(t, []int{0, 4}, report.Succeeded)
+		require.Equal(t, []int{2}, report.Skipped)
+	})
+
+	t.Run("errors ignored", func(t *testing.T) {
+		require.NoError(t, Run(tasks, 1, 0))
+
+		report, err := RunReport(context.Background(), wrapTasks(tasks), 1, 0)
+		require.NoError(t, err)
+		require.Len(t, report.Failed, 2)
+		require.Equal(t, []int{0, 4}, report.Succeeded)
+	})
+}
+
 func BenchmarkTasks(b *testing.B) {
 	tasksCount := 100
 	errorsAllowed := 10